@@ -0,0 +1,180 @@
+// Package api provides the client for the dodo API server.
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultTimeout   = 30 * time.Second
+	DefaultUserAgent = "dodo-cli"
+)
+
+var ErrNoHost = errors.New("the endpoint URL must include a scheme and a host")
+
+// Client sends requests to the dodo API server.
+// The authentication, the User-Agent, the timeout and the retries are configured once and applied to every request.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	bearerToken string
+	userAgent   string
+	header      http.Header
+	retry       RetryPolicy
+}
+
+type Option func(*Client)
+
+// WithTimeout sets the time limit of a single attempt, including reading the response body. 0 means no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithTransport replaces the transport of the underlying http.Client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+func WithRetry(retry RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithHeader adds a header that is sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithBearerToken sets the token sent in the Authorization header. No header is sent if the token is empty.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.bearerToken = token
+	}
+}
+
+func NewClient(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the endpoint URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, ErrNoHost
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  DefaultUserAgent,
+		header:     http.Header{},
+		retry:      NewRetryPolicy(DefaultMaxRetries),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+func (c *Client) BaseURL() string {
+	return c.baseURL.String()
+}
+
+// URL returns the base URL joined with the path elements.
+func (c *Client) URL(elements ...string) string {
+	return c.baseURL.JoinPath(elements...).String()
+}
+
+// NewRequest creates a request with the authentication and the common headers.
+// rawURL must be absolute, e.g. the one returned by URL().
+func (c *Client) NewRequest(method, rawURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a new request: %w", err)
+	}
+	for key, values := range c.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	return req, nil
+}
+
+// Do sends the request built by newRequest with the retry policy of the client.
+// newRequest is called for every attempt. The response is returned as is, whatever its status is.
+func (c *Client) Do(newRequest func() (*http.Request, error)) (*http.Response, error) {
+	resp, err := c.retry.Do(c.httpClient, newRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send a request to the server: %w", err)
+	}
+	return resp, nil
+}
+
+// DoJSON sends `in` as the JSON body, unless it is nil, and decodes the response into `out`, unless it is nil.
+// A response with a non-2xx status is returned as *Error.
+func (c *Client) DoJSON(method, rawURL string, in, out any) error {
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the request body: %w", err)
+		}
+		body = b
+	}
+
+	resp, err := c.Do(func() (*http.Request, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := c.NewRequest(method, rawURL, reader)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if !IsSuccess(resp.StatusCode) {
+		return NewError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse the response: %w", err)
+	}
+	return nil
+}
+
+func IsSuccess(status int) bool {
+	return status >= http.StatusOK && status < http.StatusMultipleChoices
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"https://example.com", "https://example.com", false},
+		{"http://localhost:8080", "http://localhost:8080", false},
+		{"https://api.dodo.dev", "https://api.dodo.dev", false},
+		{"https://example.com/path", "https://example.com/path", false},
+		{"", "", true},
+		{"example.com", "", true},
+		{"invalid-url", "", true},
+		{"/path/only", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := NewClient(test.input)
+		if test.hasError {
+			require.Error(t, err, "Expected error for input: %s", test.input)
+		} else {
+			require.NoError(t, err, "Unexpected error for input: %s", test.input)
+			assert.Equal(t, test.expected, result.BaseURL(), "NewClient(%s) = %v, expected %v", test.input, result.BaseURL(), test.expected)
+		}
+	}
+}
+
+func TestClient_SearchURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		expected string
+	}{
+		{"https://example.com", "https://example.com/search/v1"},
+		{"https://example.com/", "https://example.com/search/v1"},
+		{"http://localhost:8080", "http://localhost:8080/search/v1"},
+		{"https://api.dodo.dev/base", "https://api.dodo.dev/base/search/v1"},
+	}
+
+	for _, test := range tests {
+		client, err := NewClient(test.endpoint)
+		require.NoError(t, err)
+		result := client.SearchURL()
+		assert.Equal(t, test.expected, result, "Client(%s).SearchURL() = %v, expected %v", test.endpoint, result, test.expected)
+	}
+}
+
+func TestClient_DocumentURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		slug     string
+		path     string
+		expected string
+	}{
+		{"https://example.com", "myproject", "doc.md", "https://example.com/document/v1/myproject/doc.md?format=markdown"},
+		{"https://example.com/", "myproject", "folder/doc.md", "https://example.com/document/v1/myproject/folder/doc.md?format=markdown"},
+		{"http://localhost:8080", "test", "index.md", "http://localhost:8080/document/v1/test/index.md?format=markdown"},
+		{"https://api.dodo.dev/base", "proj", "docs/readme.md", "https://api.dodo.dev/base/document/v1/proj/docs/readme.md?format=markdown"},
+	}
+
+	for _, test := range tests {
+		client, err := NewClient(test.endpoint)
+		require.NoError(t, err)
+		result := client.DocumentURL(test.slug, test.path)
+		assert.Equal(t, test.expected, result, "Client(%s).DocumentURL(%s, %s) = %v, expected %v", test.endpoint, test.slug, test.path, result, test.expected)
+	}
+}
+
+func TestClient_LayoutURL(t *testing.T) {
+	client, err := NewClient("https://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/layout/v1/myproject", client.LayoutURL("myproject"))
+}
+
+func TestClientHeaders(t *testing.T) {
+	t.Parallel()
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"status":"ok","records":[{"id":"1","title":"Title"}]}`)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL,
+		WithBearerToken("token"),
+		WithUserAgent("dodo-cli/1.2.3"),
+		WithHeader("X-Custom", "value"),
+	)
+	require.NoError(t, err)
+	resp, err := client.Search(openapi.SearchPostRequest{Query: "query"})
+	require.NoError(t, err)
+	require.Len(t, resp.Records, 1)
+	assert.Equal(t, "Title", resp.Records[0].Title)
+
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "dodo-cli/1.2.3", header.Get("User-Agent"))
+	assert.Equal(t, "value", header.Get("X-Custom"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+}
+
+func TestClientError(t *testing.T) {
+	t.Parallel()
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(openapi.ErrorResponse{Status: openapi.Error, Message: "document not found"}) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	_, err = client.GetDocument("slug", "path")
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "document not found", apiErr.Message)
+	assert.Equal(t, int32(1), count.Load(), "4xx should not be retried")
+}
+
+func TestClientTimeoutAndRetry(t *testing.T) {
+	t.Parallel()
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if count.Add(1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte(`{"status":"ok","projects":[]}`)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	delays := []time.Duration{}
+	client, err := NewClient(server.URL, WithTimeout(20*time.Millisecond), WithRetry(newTestRetryPolicy(1, &delays)))
+	require.NoError(t, err)
+	projects, err := client.ListProjects()
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+	assert.Equal(t, int32(2), count.Load(), "the request that timed out should be retried")
+	assert.Len(t, delays, 1)
+}
+
+func TestClientTransport(t *testing.T) {
+	t.Parallel()
+	var called bool
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status":"ok","projects":[]}`)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, WithTransport(transport))
+	require.NoError(t, err)
+	_, err = client.ListProjects()
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestErrorKind(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		status   int
+		expected error
+	}{
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusPaymentRequired, ErrQuotaExceeded},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusRequestEntityTooLarge, ErrQuotaExceeded},
+		{http.StatusUnprocessableEntity, ErrValidation},
+		{http.StatusTooManyRequests, ErrQuotaExceeded},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusConflict, nil},
+	}
+
+	for _, test := range tests {
+		apiErr := &Error{StatusCode: test.status, Message: "message"}
+		assert.Equal(t, test.expected, apiErr.Kind(), "status %d", test.status)
+
+		err := fmt.Errorf("wrapped: %w", apiErr)
+		if test.expected != nil {
+			require.ErrorIs(t, err, test.expected, "status %d", test.status)
+		}
+		for _, other := range []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrQuotaExceeded, ErrValidation, ErrServer} {
+			if other != test.expected { //nolint:errorlint
+				assert.NotErrorIs(t, err, other, "status %d", test.status)
+			}
+		}
+	}
+	assert.Equal(t, "the server returned status 404 (not found): document not found", (&Error{StatusCode: 404, Message: "document not found"}).Error())
+	assert.Equal(t, "the server returned status 409", (&Error{StatusCode: 409}).Error())
+}
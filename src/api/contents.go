@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+const (
+	SearchPath   = "search/v1"
+	DocumentPath = "document/v1"
+	ProjectsPath = "projects/v1"
+	LayoutPath   = "layout/v1"
+	AssetPath    = "asset/v1"
+)
+
+func (c *Client) SearchURL() string {
+	return c.URL(SearchPath)
+}
+
+func (c *Client) DocumentURL(slug, path string) string {
+	return c.URL(DocumentPath, slug, path) + "?format=markdown"
+}
+
+func (c *Client) ProjectsURL() string {
+	return c.URL(ProjectsPath)
+}
+
+func (c *Client) LayoutURL(slug string) string {
+	return c.URL(LayoutPath, slug)
+}
+
+func (c *Client) AssetURL(slug, path string) string {
+	return c.URL(AssetPath, slug, path)
+}
+
+// Search runs a full-text search over the documents the user can access.
+func (c *Client) Search(body openapi.SearchPostRequest) (*openapi.SearchPostResponse, error) {
+	data := openapi.SearchPostResponse{}
+	if err := c.DoJSON(http.MethodPost, c.SearchURL(), body, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetDocument returns the document at the path of the project in markdown.
+func (c *Client) GetDocument(slug, path string) (*openapi.DocumentGetResponse, error) {
+	data := openapi.DocumentGetResponse{}
+	if err := c.DoJSON(http.MethodGet, c.DocumentURL(slug, path), nil, &data); err != nil {
+		return nil, err
+	}
+	if data.Markdown == nil {
+		return nil, errors.New("the response does not contain markdown data")
+	}
+	return &data, nil
+}
+
+// ListProjects returns the projects of the organizations the user belongs to.
+func (c *Client) ListProjects() ([]openapi.Project, error) {
+	data := openapi.ProjectsGetResponse{}
+	if err := c.DoJSON(http.MethodGet, c.ProjectsURL(), nil, &data); err != nil {
+		return nil, err
+	}
+	return data.Projects, nil
+}
+
+// GetLayout returns the page tree of the deployed project.
+func (c *Client) GetLayout(slug string) (*openapi.LayoutGetResponse, error) {
+	data := openapi.LayoutGetResponse{}
+	if err := c.DoJSON(http.MethodGet, c.LayoutURL(slug), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAsset returns the contents of the asset at the path of the project.
+func (c *Client) GetAsset(slug, path string) ([]byte, error) {
+	rawURL := c.AssetURL(slug, path)
+	resp, err := c.Do(func() (*http.Request, error) {
+		return c.NewRequest(http.MethodGet, rawURL, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if !IsSuccess(resp.StatusCode) {
+		return nil, NewError(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the asset: %w", err)
+	}
+	return data, nil
+}
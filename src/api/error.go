@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+// maxErrorBodySize limits how much of an error response is read to find the message.
+const maxErrorBodySize = 1 << 20
+
+// The kinds of errors returned by the server. Check them with errors.Is.
+var (
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrValidation    = errors.New("invalid request")
+	ErrServer        = errors.New("server error")
+)
+
+// Error is returned when the server responds with a non-2xx status.
+type Error struct {
+	StatusCode int
+	Message    string // the `message` field of the response, if any
+}
+
+// NewError reads the message from the body of an error response.
+// All the error schemas of the API (`error_response`, `search_post_response_error`, ...) share the `message` field.
+func NewError(resp *http.Response) *Error {
+	data := openapi.ErrorResponse{}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil {
+		// The body is not always JSON, e.g. when a proxy returns the error.
+		json.Unmarshal(body, &data) //nolint:errcheck
+	}
+	return &Error{
+		StatusCode: resp.StatusCode,
+		Message:    data.Message,
+	}
+}
+
+func (e *Error) Error() string {
+	prefix := fmt.Sprintf("the server returned status %d", e.StatusCode)
+	if kind := e.Kind(); kind != nil {
+		prefix = fmt.Sprintf("%s (%s)", prefix, kind)
+	}
+	if e.Message == "" {
+		return prefix
+	}
+	return fmt.Sprintf("%s: %s", prefix, e.Message)
+}
+
+// Kind returns the sentinel error which matches the status code, or nil if none does.
+func (e *Error) Kind() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusPaymentRequired,
+		e.StatusCode == http.StatusRequestEntityTooLarge,
+		e.StatusCode == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case e.StatusCode == http.StatusBadRequest,
+		e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// Is makes errors.Is(err, ErrNotFound) and the like work on *Error.
+func (e *Error) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target //nolint:errorlint
+}
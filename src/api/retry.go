@@ -0,0 +1,118 @@
+package api
+
+import (
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/caarlos0/log"
+)
+
+const (
+	DefaultMaxRetries     = 4
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy retries a request on network errors, 5xx and 429 responses.
+// The delay grows exponentially with full jitter. A `Retry-After` header from the server takes precedence,
+// but it is capped by MaxDelay, so that a misbehaving server or proxy cannot stall the CLI for hours.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// Sleep waits between the attempts. time.Sleep is used if nil. Tests replace it to record the delays.
+	Sleep func(time.Duration)
+}
+
+func NewRetryPolicy(maxRetries int) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  DefaultRetryBaseDelay,
+		MaxDelay:   DefaultRetryMaxDelay,
+	}
+}
+
+// Do sends the request built by newRequest until it succeeds or the retries are exhausted.
+// newRequest is called for every attempt, because a request body can only be read once.
+// The last response is returned as is, even if its status is an error, so that the caller can parse the message.
+func (p RetryPolicy) Do(client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if attempt >= p.MaxRetries {
+			return resp, err //nolint:wrapcheck
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			log.Warnf("request to %s failed: %v", req.URL.Path, err)
+			delay = p.backoff(attempt)
+		case isRetryableStatus(resp.StatusCode):
+			log.Warnf("request to %s failed with status %d", req.URL.Path, resp.StatusCode)
+			delay = p.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				delay = min(retryAfter, p.MaxDelay)
+			}
+			// Drain the body so that the connection can be reused.
+			io.Copy(io.Discard, resp.Body) //nolint:errcheck
+			resp.Body.Close()              //nolint:errcheck
+		default:
+			return resp, nil
+		}
+
+		log.Infof("retrying in %s (%d/%d)", delay.Round(time.Millisecond), attempt+1, p.MaxRetries)
+		p.wait(delay)
+	}
+}
+
+// backoff returns a random delay in [0, min(MaxDelay, BaseDelay * 2^attempt)].
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << min(attempt, 30)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+func (p RetryPolicy) wait(d time.Duration) {
+	if p.Sleep != nil {
+		p.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// parseRetryAfter supports both forms of the header: delay in seconds and an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		// Avoid the overflow of time.Duration. The delay is capped by the policy anyway.
+		return time.Duration(min(int64(seconds), math.MaxInt64/int64(time.Second))) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRetryPolicy returns a policy that records the delays instead of sleeping.
+func newTestRetryPolicy(maxRetries int, delays *[]time.Duration) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   10 * time.Millisecond,
+		Sleep: func(d time.Duration) {
+			*delays = append(*delays, d)
+		},
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	t.Parallel()
+
+	// statuses are returned in order, then 200.
+	newServer := func(statuses []int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+		var count atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			n := int(count.Add(1)) - 1
+			if n < len(statuses) {
+				if retryAfter != "" {
+					w.Header().Set("Retry-After", retryAfter)
+				}
+				w.WriteHeader(statuses[n])
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server, &count
+	}
+	do := func(policy RetryPolicy, url string) *http.Response {
+		resp, err := policy.Do(&http.Client{}, func() (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, url, nil)
+		})
+		require.NoError(t, err)
+		resp.Body.Close() //nolint:errcheck
+		return resp
+	}
+
+	t.Run("retry on 5xx with backoff", func(t *testing.T) {
+		t.Parallel()
+		server, count := newServer([]int{http.StatusServiceUnavailable, http.StatusBadGateway}, "")
+		delays := []time.Duration{}
+		resp := do(newTestRetryPolicy(3, &delays), server.URL)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), count.Load())
+		require.Len(t, delays, 2)
+		assert.LessOrEqual(t, delays[0], time.Millisecond)
+		assert.LessOrEqual(t, delays[1], 2*time.Millisecond)
+	})
+
+	t.Run("honor Retry-After on 429", func(t *testing.T) {
+		t.Parallel()
+		server, count := newServer([]int{http.StatusTooManyRequests}, "7")
+		delays := []time.Duration{}
+		policy := newTestRetryPolicy(3, &delays)
+		policy.MaxDelay = time.Minute
+		resp := do(policy, server.URL)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), count.Load())
+		assert.Equal(t, []time.Duration{7 * time.Second}, delays)
+	})
+
+	t.Run("cap Retry-After by MaxDelay", func(t *testing.T) {
+		t.Parallel()
+		for _, retryAfter := range []string{"86400", time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat), "99999999999999"} {
+			server, count := newServer([]int{http.StatusServiceUnavailable}, retryAfter)
+			delays := []time.Duration{}
+			resp := do(newTestRetryPolicy(3, &delays), server.URL)
+			assert.Equal(t, http.StatusOK, resp.StatusCode, retryAfter)
+			assert.Equal(t, int32(2), count.Load(), retryAfter)
+			assert.Equal(t, []time.Duration{10 * time.Millisecond}, delays, retryAfter)
+		}
+	})
+
+	t.Run("do not retry on 4xx", func(t *testing.T) {
+		t.Parallel()
+		server, count := newServer([]int{http.StatusBadRequest}, "")
+		delays := []time.Duration{}
+		resp := do(newTestRetryPolicy(3, &delays), server.URL)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, int32(1), count.Load())
+		assert.Empty(t, delays)
+	})
+
+	t.Run("return the last response when retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+		server, count := newServer([]int{500, 500, 500, 500}, "")
+		delays := []time.Duration{}
+		resp := do(newTestRetryPolicy(2, &delays), server.URL)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, int32(3), count.Load())
+		assert.Len(t, delays, 2)
+	})
+
+	t.Run("retry on network errors", func(t *testing.T) {
+		t.Parallel()
+		delays := []time.Duration{}
+		_, err := newTestRetryPolicy(2, &delays).Do(&http.Client{}, func() (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, "http://127.0.0.1:1", nil)
+		})
+		require.Error(t, err)
+		assert.Len(t, delays, 2)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Zero(t, d)
+
+	for _, v := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(v, now)
+		assert.False(t, ok, v)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := range 10 {
+		delay := policy.backoff(attempt)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, min(100*time.Millisecond<<attempt, time.Second))
+	}
+}
@@ -2,9 +2,10 @@
 package main
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/caarlos0/log"
 	"github.com/spf13/cobra"
@@ -15,6 +16,7 @@ type CheckArgs struct {
 	configPath string // config file path
 	debug      bool   // enable debug mode
 	noColor    bool   // disable color output
+	watch      bool   // re-validate the project when files change
 }
 
 // Implement LoggingConfig interface for CheckArgs.
@@ -41,7 +43,7 @@ func CreateCheckCmd() *cobra.Command {
 		Short:         "check the configuration file for dodo-doc",
 		SilenceErrors: true,
 		SilenceUsage:  true,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			printer := NewErrorPrinter(ErrorLevel)
 			if err := InitLogger(&opts); err != nil {
 				return printer.HandleError(err)
@@ -53,6 +55,11 @@ func CreateCheckCmd() *cobra.Command {
 			}
 			printer = NewPrinterFromArgs(&opts)
 
+			if opts.watch {
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+				defer stop()
+				return watchCheckCmd(ctx, opts, printer)
+			}
 			if err := checkCmdEntrypoint(opts); err != nil {
 				return printer.HandleError(err)
 			}
@@ -62,9 +69,29 @@ func CreateCheckCmd() *cobra.Command {
 	checkCmd.Flags().StringVarP(&opts.configPath, "config", "c", ".dodo.yaml", "Path to the configuration file")
 	checkCmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
 	checkCmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	checkCmd.Flags().BoolVar(&opts.watch, "watch", false, "Watch the project files and re-validate on changes")
 	return checkCmd
 }
 
+// watchCheckCmd validates the project once and then again every time the project files change.
+// Validation errors are printed but do not stop the watcher.
+func watchCheckCmd(ctx context.Context, args CheckArgs, printer *ErrorPrinter) error {
+	runCheck := func() {
+		if err := checkCmdEntrypoint(args); err != nil {
+			printer.HandleError(err) //nolint:errcheck
+			return
+		}
+		log.Infof("no problems found in the configuration")
+	}
+
+	runCheck()
+	watcher := NewWatcher(args.configPath)
+	return watcher.Run(ctx, func() {
+		log.Infof("change detected. re-validating the project")
+		runCheck()
+	})
+}
+
 func checkCmdEntrypoint(args CheckArgs) error { //nolint: cyclop
 	// Read config file
 	log.Debugf("config file: %s", args.configPath)
@@ -124,7 +151,7 @@ func CheckArgsAndEnvForCheck(args CheckArgs, env EnvArgs) error {
 
 	// Check if any auth credential is available
 	if !env.IsAuthenticated() {
-		return errors.New("not authenticated. Please run 'dodo login' or set the DODO_API_KEY environment variable")
+		return ErrNotAuthenticated
 	}
 	return nil
 }
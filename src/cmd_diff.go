@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/spf13/cobra"
+)
+
+var AvailableDiffFormat = []string{ //nolint: gochecknoglobals
+	FormatText,
+	FormatJSON,
+}
+
+type DiffArgs struct {
+	file      string // path to the config file
+	format    string // output format
+	debug     bool   // enable debug mode
+	noColor   bool   // disable color output
+	projectID string // override project_id from config
+	endpoint  string // the endpoint of the dodo API server
+}
+
+// Implement LoggingConfig and PrinterConfig interface for DiffArgs.
+func (opts *DiffArgs) DisableLogging() bool {
+	return opts.format == FormatJSON
+}
+
+func (opts *DiffArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *DiffArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *DiffArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateDiffCmd() *cobra.Command {
+	opts := DiffArgs{}
+	cmd := &cobra.Command{
+		Use:           "diff",
+		Short:         "Show what an upload would change on the deployed project",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			env := NewEnvArgs()
+			if err := CheckArgsAndEnvForDiff(opts, env); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			if err := diffCmdEntrypoint(opts, env, os.Stdout); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.file, "config", "c", ".dodo.yaml", "Path to the configuration file")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", FormatText, fmt.Sprintf("Output format. Available values: [%s]", strings.Join(AvailableDiffFormat, ", ")))
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Override the project_id from the config file")
+	cmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com/", "The endpoint of the dodo API server")
+	return cmd
+}
+
+func CheckArgsAndEnvForDiff(args DiffArgs, env EnvArgs) error {
+	if !env.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if _, err := os.Stat(args.file); err != nil {
+		return fmt.Errorf("specified config file is invalid. Please check if the file exists. Path: %s", args.file)
+	}
+	if !slices.Contains(AvailableDiffFormat, args.format) {
+		return fmt.Errorf("unknown format: %s", args.format)
+	}
+	if args.format == FormatJSON && args.debug {
+		return errors.New("debug mode is not supported in json format")
+	}
+	return nil
+}
+
+func diffCmdEntrypoint(args DiffArgs, env EnvArgs, w io.Writer) error {
+	diff, err := executeDiff(args, env)
+	if err != nil {
+		return err
+	}
+
+	output := diff.Text()
+	if args.format == FormatJSON {
+		b, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the output: %w", err)
+		}
+		output = string(b) + "\n"
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+func executeDiff(args DiffArgs, env EnvArgs) (*ProjectDiff, error) {
+	metadata, err := loadMetadataFromConfig(args.file, args.projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectID := metadata.Project.ProjectID
+
+	client, err := NewAPIClient(args.endpoint, env)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Sending request to the %s", client.LayoutURL(projectID))
+	layout, err := NewLayoutFromAPI(client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetch := func(path string) (string, error) {
+		log.Debugf("Fetching the deployed page %s", path)
+		return sendReadDocumentRequest(client, projectID, path)
+	}
+	return NewProjectDiff(projectID, &metadata.Page, &layout.Page, fetch)
+}
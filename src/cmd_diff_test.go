@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/utils"
+)
+
+// newDiffServer serves the layout of the deployed project and the markdown of page1.
+// page1 has been moved into a section and edited, page2 is unchanged and page3 has been removed since.
+func newDiffServer(t *testing.T, dir string) (*httptest.Server, *[]string) {
+	t.Helper()
+	page2Hash, _, err := utils.HashFile(filepath.Join(dir, "page2.md"))
+	require.NoError(t, err)
+	layout := fmt.Sprintf(`{"status": "ok", "is_public": true, "allowed_orgs": [], "layout": {
+	  "type": "RootNode",
+	  "children": [
+	    {"type": "SectionNode", "language": [{"language": "en", "title": "Old"}], "children": [
+	      {"type": "LeafNode", "language": [{"language": "en", "title": "Page 1", "path": "page1", "hash": "old"}], "children": []}
+	    ]},
+	    {"type": "LeafNode", "language": [{"language": "en", "title": "Page 2", "path": "page2", "hash": %q}], "children": []},
+	    {"type": "LeafNode", "language": [{"language": "en", "title": "Page 3", "path": "page3", "hash": "page3"}], "children": []}
+	  ]
+	}}`, page2Hash)
+
+	documents := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /layout/v1/project_id", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(layout)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /document/v1/project_id/{path}", func(w http.ResponseWriter, r *http.Request) {
+		documents = append(documents, r.PathValue("path"))
+		w.Write([]byte(`{"status": "ok", "is_public": true, "allowed_orgs": [], "markdown": "# Page 1\nOld text"}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &documents
+}
+
+func TestDiffText(t *testing.T) {
+	dir := prepareUploadProject(t)
+	server, documents := newDiffServer(t, dir)
+	args := DiffArgs{file: ".dodo.yaml", format: FormatText, endpoint: server.URL}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, diffCmdEntrypoint(args, EnvArgs{APIKey: "test-token"}, out))
+	expected := `Removed (1)
+  - [en] page3  Page 3
+
+Moved (1)
+  ~ [en] page1  Page 1 (Old -> (top))
+
+Modified (1)
+  * [en] page1  Page 1
+
+--- deployed:page1
++++ page1.md
+@@ -1,2 +1 @@
+ # Page 1
+-Old text
+
+0 added, 1 removed, 1 moved, 1 modified
+`
+	assert.Equal(t, expected, out.String())
+	assert.Equal(t, []string{"page1"}, *documents, "unchanged pages should not be fetched")
+}
+
+func TestDiffJSON(t *testing.T) {
+	dir := prepareUploadProject(t)
+	prepareFile(t, dir, "page4.md", "# Page 4")
+	prepareFile(t, dir, ".dodo.yaml", uploadTestConfig+`  - type: markdown
+    lang:
+      en:
+        filepath: "page4.md"
+        title: "Page 4"
+        link: "page4"
+`)
+	server, _ := newDiffServer(t, dir)
+	args := DiffArgs{file: ".dodo.yaml", format: FormatJSON, endpoint: server.URL}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, diffCmdEntrypoint(args, EnvArgs{APIKey: "test-token"}, out))
+	diff := ProjectDiff{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &diff))
+	assert.Equal(t, "project_id", diff.ProjectID)
+
+	statuses := []string{}
+	for _, p := range diff.Pages {
+		statuses = append(statuses, p.Status+" "+p.Path)
+	}
+	assert.Equal(t, []string{"moved page1", "modified page1", "added page4", "removed page3"}, statuses)
+	assert.Equal(t, "Old", diff.Pages[0].OldLocation)
+	assert.Contains(t, diff.Pages[1].Diff, "-Old text")
+	assert.Equal(t, "page4.md", diff.Pages[2].Filepath)
+}
+
+func TestProjectDiffNoChanges(t *testing.T) {
+	t.Parallel()
+	page := Page{Type: PageTypeRootNode, Children: []Page{{
+		Type:     PageTypeLeafNode,
+		Language: []PageLanguageWiseInfo{{Language: "en", Title: "Page", Path: "page", Filepath: "page.md", Hash: "hash"}},
+	}}}
+	fetch := func(string) (string, error) {
+		t.Fatal("pages with the same hash should not be fetched")
+		return "", nil
+	}
+	diff, err := NewProjectDiff("project_id", &page, &page, fetch)
+	require.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+	assert.Equal(t, "No changes. The deployed project project_id is up to date.\n", diff.Text())
+}
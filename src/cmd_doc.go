@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/caarlos0/log"
 	"github.com/charmbracelet/bubbles/list"
@@ -13,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/api"
 )
 
 type DocArgs struct {
@@ -64,7 +66,7 @@ func CreateDocCmd() *cobra.Command {
 	}
 	docsCmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
 	docsCmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
-	docsCmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com/projects/v1", "The endpoint of the dodo API server")
+	docsCmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com", "The endpoint of the dodo API server")
 	docsCmd.Flags().StringVar(&opts.format, "format", FormatTUI, "Output format for the command. Supported formats: {tui, json}")
 
 	return docsCmd
@@ -75,8 +77,14 @@ func docCmdEntrypoint(args *DocArgs, env *EnvArgs) error {
 		return err
 	}
 
-	log.Debugf("Sending request to the %s", args.endpoint)
-	orgs, err := NewProjectFromAPI(env, args.endpoint)
+	// The endpoint used to be the full URL of the projects API. Accept it for compatibility.
+	endpoint := strings.TrimSuffix(strings.TrimSuffix(args.endpoint, "/"), "/"+api.ProjectsPath)
+	client, err := NewAPIClient(endpoint, *env)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Sending request to the %s", client.ProjectsURL())
+	orgs, err := NewProjectFromAPI(client)
 	if err != nil {
 		return err
 	}
@@ -98,7 +106,7 @@ func docCmdEntrypoint(args *DocArgs, env *EnvArgs) error {
 
 func CheckArgsAndEnvForDocs(args *DocArgs, env *EnvArgs) error {
 	if !env.IsAuthenticated() {
-		return errors.New("not authenticated. Please run 'dodo login' or set the DODO_API_KEY environment variable")
+		return ErrNotAuthenticated
 	}
 	if args.endpoint == "" {
 		return errors.New("endpoint is not set")
@@ -156,7 +164,7 @@ func (m DocTUIModel) View() string {
 	return m.list.View()
 }
 
-func NewDocTUIModel(orgs []Project) DocTUIModel { //nolint:funlen
+func NewDocTUIModel(orgs []Project) DocTUIModel {
 	items := make([]list.Item, 0, len(orgs))
 
 	for _, org := range orgs {
@@ -169,6 +177,13 @@ func NewDocTUIModel(orgs []Project) DocTUIModel { //nolint:funlen
 		})
 	}
 
+	return DocTUIModel{
+		list: newStyledList(items),
+	}
+}
+
+// newStyledList creates a filterable list with the colors of dodo.
+func newStyledList(items []list.Item) list.Model { //nolint:funlen
 	w, h, _ := term.GetSize(os.Stdout.Fd())
 	delegate := list.NewDefaultDelegate()
 
@@ -226,10 +241,7 @@ func NewDocTUIModel(orgs []Project) DocTUIModel { //nolint:funlen
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(true)
 	l.DisableQuitKeybindings()
-
-	return DocTUIModel{
-		list: l,
-	}
+	return l
 }
 
 func renderProjectsWithTUI(orgs []Project) error {
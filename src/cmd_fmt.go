@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/caarlos0/log"
+	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/config"
+	"github.com/toritoritori29/dodo-cli/src/utils"
+)
+
+// The `fmt` command rewrites the config into the canonical form, so that reviews do not argue over the YAML style.
+
+type FmtArgs struct {
+	configPath string // config file path
+	check      bool   // report the diff instead of writing the file
+	debug      bool   // enable debug mode
+	noColor    bool   // disable color output
+}
+
+// Implement LoggingConfig and PrinterConfig interface for FmtArgs.
+func (opts *FmtArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *FmtArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *FmtArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *FmtArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateFmtCmd() *cobra.Command {
+	opts := FmtArgs{}
+	cmd := &cobra.Command{
+		Use:           "fmt",
+		Short:         "Format the configuration file",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			if err := CheckArgsForFmt(opts); err != nil {
+				return printer.HandleError(err)
+			}
+			printer = NewPrinterFromArgs(&opts)
+			if err := executeFmt(opts, os.Stdout); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.configPath, "config", "c", ".dodo.yaml", "Path to the configuration file")
+	cmd.Flags().BoolVar(&opts.check, "check", false, "Exit with an error and print the diff if the file is not formatted, instead of writing it")
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	return cmd
+}
+
+func CheckArgsForFmt(args FmtArgs) error {
+	if _, err := os.Stat(args.configPath); err != nil {
+		return fmt.Errorf("specified `config` argument is invalid. Please check if the file exists. Path: %s", args.configPath)
+	}
+	return nil
+}
+
+// executeFmt formats the config in place. In check mode, it writes the diff to w and fails if the config is not formatted.
+func executeFmt(args FmtArgs, w io.Writer) error {
+	contents, err := os.ReadFile(args.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to open the config file: %w", err)
+	}
+	formatted, err := config.FormatConfig(bytes.NewReader(contents))
+	if err != nil {
+		return fmt.Errorf("failed to format the config file: %w", err)
+	}
+	if bytes.Equal(contents, formatted) {
+		log.Infof("%s is already formatted", args.configPath)
+		return nil
+	}
+
+	if args.check {
+		diff, err := unifiedDiff(string(contents), string(formatted), args.configPath, args.configPath+" (formatted)")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, diff); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return fmt.Errorf("%s is not formatted. Run `dodo fmt` to format it", args.configPath)
+	}
+
+	if err := utils.WriteFileKeepingMode(args.configPath, formatted); err != nil {
+		return fmt.Errorf("failed to write the config file: %w", err)
+	}
+	log.Infof("formatted %s", args.configPath)
+	return nil
+}
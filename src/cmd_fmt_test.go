@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fmtTestConfig = `version: 2
+project:
+  name: "Test Project"
+  project_id: project_id
+pages:
+- type: markdown
+  filepath: README.md
+`
+
+func TestExecuteFmt(t *testing.T) {
+	dir := prepareUploadProject(t)
+	prepareFile(t, dir, ".dodo.yaml", fmtTestConfig)
+
+	// Check mode reports the diff and does not touch the config.
+	out := &bytes.Buffer{}
+	err := executeFmt(FmtArgs{configPath: ".dodo.yaml", check: true}, out)
+	require.ErrorContains(t, err, ".dodo.yaml is not formatted")
+	assert.Contains(t, out.String(), "-  name: \"Test Project\"")
+	assert.Contains(t, out.String(), "+  name: Test Project")
+	contents, err := os.ReadFile(".dodo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, fmtTestConfig, string(contents))
+
+	require.NoError(t, executeFmt(FmtArgs{configPath: ".dodo.yaml"}, out))
+	expected := `version: 2
+project:
+  project_id: project_id
+  name: Test Project
+pages:
+  - type: markdown
+    filepath: README.md
+`
+	contents, err = os.ReadFile(".dodo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(contents))
+
+	// The formatted config passes the check.
+	out.Reset()
+	require.NoError(t, executeFmt(FmtArgs{configPath: ".dodo.yaml", check: true}, out))
+	assert.Empty(t, out.String())
+}
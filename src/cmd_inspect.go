@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var AvailableInspectFormat = []string{ //nolint: gochecknoglobals
+	FormatText,
+	FormatJSON,
+	FormatTree,
+}
+
+type InspectArgs struct {
+	target    string // an archive built by `dodo pack` or a config file
+	format    string // output format
+	debug     bool   // enable debug mode
+	noColor   bool   // disable color output
+	projectID string // override project_id from config
+}
+
+// Implement LoggingConfig and PrinterConfig interface for InspectArgs.
+func (opts *InspectArgs) DisableLogging() bool {
+	return opts.format == FormatJSON
+}
+
+func (opts *InspectArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *InspectArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *InspectArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateInspectCmd() *cobra.Command {
+	opts := InspectArgs{}
+	cmd := &cobra.Command{
+		Use:           "inspect [archive.zip | .dodo.yaml]",
+		Short:         "show the metadata, the page tree and the assets that are shipped to dodo",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			opts.target = ".dodo.yaml"
+			if len(args) > 0 {
+				opts.target = args[0]
+			}
+
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			if err := CheckArgsForInspect(opts); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			if err := inspectCmdEntrypoint(opts, os.Stdout); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.format, "format", "f", FormatText, fmt.Sprintf("Output format. Available values: [%s]", strings.Join(AvailableInspectFormat, ", ")))
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Override the project_id from the config file")
+	return cmd
+}
+
+func CheckArgsForInspect(args InspectArgs) error {
+	// Check if `target` is valid
+	_, err := os.Stat(args.target)
+	if err != nil && os.IsNotExist(err) {
+		return fmt.Errorf("specified file is invalid. Please check if the file exists. Path: %s", args.target)
+	}
+	if err != nil {
+		return fmt.Errorf("specified file is invalid. Path: %s", args.target)
+	}
+
+	if !slices.Contains(AvailableInspectFormat, args.format) {
+		return fmt.Errorf("unknown format: %s", args.format)
+	}
+	if args.format == FormatJSON && args.debug {
+		return errors.New("debug mode is not supported in json format")
+	}
+	return nil
+}
+
+func inspectCmdEntrypoint(args InspectArgs, w io.Writer) error {
+	inspection, err := inspectTarget(args)
+	if err != nil {
+		return err
+	}
+
+	var output string
+	switch args.format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(inspection, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the output: %w", err)
+		}
+		output = string(b) + "\n"
+	case FormatTree:
+		output = inspection.Tree()
+	default:
+		output = inspection.Text()
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// inspectTarget reads the metadata from an archive, or builds it from a config file.
+func inspectTarget(args InspectArgs) (*Inspection, error) {
+	if strings.EqualFold(filepath.Ext(args.target), ".zip") {
+		archive, err := OpenArchive(args.target)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close() //nolint:errcheck
+		blobSize, err := ArchiveBlobSize(archive)
+		if err != nil {
+			return nil, err
+		}
+		return NewInspection(args.target, archive.Metadata, blobSize)
+	}
+
+	metadata, err := loadMetadataFromConfig(args.target, args.projectID)
+	if err != nil {
+		return nil, err
+	}
+	return NewInspection(args.target, metadata, FileBlobSize)
+}
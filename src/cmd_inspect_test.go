@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectCmdEntrypoint(t *testing.T) {
+	prepareUploadProject(t)
+	archive := filepath.Join(t.TempDir(), "site.zip")
+	require.NoError(t, packCmdEntrypoint(PackArgs{file: ".dodo.yaml", output: archive}))
+
+	inspect := func(target, format string) string {
+		args := InspectArgs{target: target, format: format}
+		require.NoError(t, CheckArgsForInspect(args))
+		var out bytes.Buffer
+		require.NoError(t, inspectCmdEntrypoint(args, &out))
+		return out.String()
+	}
+
+	// The config and the archive built from it describe the same payload.
+	fromConfig := inspect(".dodo.yaml", FormatJSON)
+	fromArchive := inspect(archive, FormatJSON)
+	var configInspection, archiveInspection Inspection
+	require.NoError(t, json.Unmarshal([]byte(fromConfig), &configInspection))
+	require.NoError(t, json.Unmarshal([]byte(fromArchive), &archiveInspection))
+	assert.Equal(t, "project_id", configInspection.Project.ProjectID)
+	assert.Len(t, configInspection.Page.Children, 2)
+	assert.Equal(t, configInspection.Page, archiveInspection.Page)
+	assert.Equal(t, configInspection.PayloadSize, archiveInspection.PayloadSize)
+	assert.Equal(t, configInspection.MetadataSize+int64(len("# Page 1")+len("# Page 2")), configInspection.PayloadSize)
+
+	hash := configInspection.Page.Children[0].Language[0].Hash
+	text := inspect(".dodo.yaml", FormatText)
+	assert.Contains(t, text, "Project ID:       project_id")
+	assert.Contains(t, text, "[en] Title: Page 1, Link: page1")
+	assert.Contains(t, text, "Filepath: page1.md, Hash: "+hash)
+	assert.Contains(t, text, "Total payload: ")
+
+	tree := inspect(archive, FormatTree)
+	assert.Contains(t, tree, "Test Project (project_id)")
+	assert.Contains(t, tree, "en: Page 2 -> page2 (page2.md "+configInspection.Page.Children[1].Language[0].Hash[:12]+")")
+}
+
+func TestCheckArgsForInspect(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, ".dodo.yaml", "")
+	target := filepath.Join(dir, ".dodo.yaml")
+
+	require.NoError(t, CheckArgsForInspect(InspectArgs{target: target, format: FormatTree}))
+	require.Error(t, CheckArgsForInspect(InspectArgs{target: filepath.Join(dir, "missing.zip"), format: FormatText}))
+	require.Error(t, CheckArgsForInspect(InspectArgs{target: target, format: "yaml"}))
+	require.Error(t, CheckArgsForInspect(InspectArgs{target: target, format: FormatJSON, debug: true}))
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var AvailableLayoutFormat = []string{ //nolint: gochecknoglobals
+	FormatText,
+	FormatTUI,
+	FormatJSON,
+}
+
+type LayoutArgs struct {
+	projectID string // the project ID (slug) of the deployed project
+	format    string // output format
+	debug     bool   // enable debug mode
+	noColor   bool   // disable color output
+	endpoint  string // the endpoint of the dodo API server
+}
+
+// Implement LoggingConfig and PrinterConfig interface for LayoutArgs.
+func (opts *LayoutArgs) DisableLogging() bool {
+	return opts.format == FormatJSON
+}
+
+func (opts *LayoutArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *LayoutArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *LayoutArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateLayoutCmd() *cobra.Command {
+	opts := LayoutArgs{}
+	cmd := &cobra.Command{
+		Use:           "layout",
+		Short:         "Show the page tree of a deployed project",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			env := NewEnvArgs()
+			if err := CheckArgsAndEnvForLayout(opts, env); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			if err := layoutCmdEntrypoint(opts, env, os.Stdout); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.projectID, "project-id", "s", "", "The project ID (slug) of the deployed project")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", FormatText, fmt.Sprintf("Output format. Available values: [%s]", strings.Join(AvailableLayoutFormat, ", ")))
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	cmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com/", "The endpoint of the dodo API server")
+	return cmd
+}
+
+func CheckArgsAndEnvForLayout(args LayoutArgs, env EnvArgs) error {
+	if !env.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if args.projectID == "" {
+		return errors.New("project ID is required. Please set --project-id")
+	}
+	if !slices.Contains(AvailableLayoutFormat, args.format) {
+		return fmt.Errorf("unknown format: %s", args.format)
+	}
+	if args.format == FormatJSON && args.debug {
+		return errors.New("debug mode is not supported in json format")
+	}
+	if args.format == FormatTUI && args.noColor {
+		return errors.New("no-color options is not supported in tui format")
+	}
+	return nil
+}
+
+func layoutCmdEntrypoint(args LayoutArgs, env EnvArgs, w io.Writer) error {
+	client, err := NewAPIClient(args.endpoint, env)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Sending request to the %s", client.LayoutURL(args.projectID))
+	layout, err := NewLayoutFromAPI(client, args.projectID)
+	if err != nil {
+		return err
+	}
+
+	switch args.format {
+	case FormatTUI:
+		return renderLayoutWithTUI(layout, w)
+	case FormatJSON:
+		return renderLayoutWithJSON(layout, w)
+	default:
+		if _, err := io.WriteString(w, layout.Text()); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+}
+
+// LayoutOutput is the JSON output of `dodo layout`.
+type LayoutOutput struct {
+	*Layout
+	Links []LayoutLink `json:"links"`
+}
+
+func renderLayoutWithJSON(layout *Layout, w io.Writer) error {
+	b, err := json.MarshalIndent(LayoutOutput{Layout: layout, Links: layout.Links()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the output: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", b); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// renderLayoutWithTUI lists the pages. The `dodo read` command of the selected page is printed on exit.
+func renderLayoutWithTUI(layout *Layout, w io.Writer) error {
+	links := layout.Links()
+	if len(links) == 0 {
+		return errors.New("the project has no pages")
+	}
+	items := make([]list.Item, 0, len(links))
+	for _, link := range links {
+		items = append(items, layoutItem{link: link})
+	}
+
+	model, err := tea.NewProgram(LayoutTUIModel{list: newStyledList(items)}).Run()
+	if err != nil {
+		return fmt.Errorf("failed to run the program: %w", err)
+	}
+	selected := model.(LayoutTUIModel).selected //nolint:forcetypeassert
+	if selected == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, layout.ReadCommand(selected.Path)); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+type LayoutTUIModel struct {
+	list     list.Model
+	selected *LayoutLink
+}
+
+func (m LayoutTUIModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m LayoutTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:ireturn
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		// Enter confirms the filter while it is being typed.
+		if msg.Type == tea.KeyEnter && m.list.FilterState() != list.Filtering {
+			if item, ok := m.list.SelectedItem().(layoutItem); ok {
+				m.selected = &item.link
+			}
+			return m, tea.Quit
+		}
+	}
+
+	var listCmd tea.Cmd
+	m.list, listCmd = m.list.Update(msg)
+	return m, listCmd
+}
+
+func (m LayoutTUIModel) View() string {
+	return m.list.View()
+}
+
+type layoutItem struct {
+	link LayoutLink
+}
+
+func (i layoutItem) Title() string {
+	return strings.Repeat("  ", i.link.Depth) + i.link.Title
+}
+
+func (i layoutItem) Description() string {
+	return fmt.Sprintf("%s%s [%s]", strings.Repeat("  ", i.link.Depth), i.link.Path, i.link.Language)
+}
+
+func (i layoutItem) FilterValue() string {
+	return i.link.Title + " " + i.link.Path
+}
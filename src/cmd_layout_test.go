@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLayoutResponse = `{
+  "status": "ok",
+  "message": "",
+  "is_public": false,
+  "allowed_orgs": ["org-1"],
+  "layout": {
+    "type": "RootNode",
+    "children": [
+      {
+        "type": "LeafNode",
+        "language": [
+          {"language": "en", "title": "Guide", "path": "/guide"},
+          {"language": "ja", "title": "Guide JA", "path": "/ja/guide"}
+        ],
+        "children": []
+      },
+      {
+        "type": "SectionNode",
+        "language": [{"language": "en", "title": "API"}],
+        "children": [
+          {
+            "type": "LeafNode",
+            "language": [{"language": "en", "title": "Search", "path": "/api/search"}],
+            "children": []
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+func newLayoutServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/layout/v1/myproject" || r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":"error","message":"project not found"}`)) //nolint:errcheck
+			return
+		}
+		w.Write([]byte(testLayoutResponse)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLayoutText(t *testing.T) {
+	t.Parallel()
+	server := newLayoutServer(t)
+	args := LayoutArgs{projectID: "myproject", format: FormatText, endpoint: server.URL}
+	out := &bytes.Buffer{}
+	require.NoError(t, layoutCmdEntrypoint(args, EnvArgs{APIKey: "test-token"}, out))
+
+	expected := `myproject (private)
+├── Guide -> /guide [en, ja]
+└── API
+    └── Search -> /api/search
+
+Allowed organizations: org-1
+
+Read a page with: dodo read --project-id myproject --path <path>
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestLayoutJSON(t *testing.T) {
+	t.Parallel()
+	server := newLayoutServer(t)
+	args := LayoutArgs{projectID: "myproject", format: FormatJSON, endpoint: server.URL}
+	out := &bytes.Buffer{}
+	require.NoError(t, layoutCmdEntrypoint(args, EnvArgs{APIKey: "test-token"}, out))
+
+	var output struct {
+		ProjectID   string       `json:"project_id"`
+		IsPublic    bool         `json:"is_public"`
+		AllowedOrgs []string     `json:"allowed_orgs"`
+		Page        Page         `json:"page"`
+		Links       []LayoutLink `json:"links"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &output))
+	assert.Equal(t, "myproject", output.ProjectID)
+	assert.Equal(t, []string{"org-1"}, output.AllowedOrgs)
+	assert.Len(t, output.Page.Children, 2)
+	assert.Equal(t, []LayoutLink{
+		{Language: "en", Title: "Guide", Path: "/guide", Depth: 0},
+		{Language: "ja", Title: "Guide JA", Path: "/ja/guide", Depth: 0},
+		{Language: "en", Title: "Search", Path: "/api/search", Depth: 1},
+	}, output.Links)
+}
+
+func TestLayoutNotFound(t *testing.T) {
+	t.Parallel()
+	server := newLayoutServer(t)
+	args := LayoutArgs{projectID: "unknown", format: FormatText, endpoint: server.URL}
+	err := layoutCmdEntrypoint(args, EnvArgs{APIKey: "test-token"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeNotFound, ExitCode(err))
+	assert.Contains(t, err.Error(), "project not found")
+}
+
+func TestDecodeLayoutPage(t *testing.T) {
+	t.Parallel()
+	// The page tree can be nested in `page` as in metadata.json.
+	page, err := decodeLayoutPage(map[string]any{
+		"page": map[string]any{"type": PageTypeRootNode, "children": []any{map[string]any{"type": PageTypeLeafNode}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, PageTypeRootNode, page.Type)
+	assert.Len(t, page.Children, 1)
+}
+
+func TestLayoutLanguagePath(t *testing.T) {
+	t.Parallel()
+	server := newLayoutServer(t)
+	client, err := NewAPIClient(server.URL, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+	layout, err := NewLayoutFromAPI(client, "myproject")
+	require.NoError(t, err)
+
+	path, err := layout.LanguagePath("/guide", "ja")
+	require.NoError(t, err)
+	assert.Equal(t, "/ja/guide", path)
+	path, err = layout.LanguagePath("ja/guide/", "en")
+	require.NoError(t, err)
+	assert.Equal(t, "/guide", path)
+
+	_, err = layout.LanguagePath("/api/search", "ja")
+	require.ErrorContains(t, err, "Available languages: en")
+	_, err = layout.LanguagePath("/missing", "en")
+	require.ErrorContains(t, err, "not found")
+}
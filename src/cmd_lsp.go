@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// The `lsp` command runs a language server for `.dodo.yaml` and the front matter of the markdown files.
+
+type LSPArgs struct {
+	debug bool // enable debug mode
+}
+
+// Implement LoggingConfig and PrinterConfig interface for LSPArgs.
+// The logs go to stderr, so that they do not mix with the messages on stdout.
+func (opts *LSPArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *LSPArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *LSPArgs) EnableColor() bool {
+	return false
+}
+
+func (opts *LSPArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateLSPCmd() *cobra.Command {
+	opts := LSPArgs{}
+	cmd := &cobra.Command{
+		Use:           "lsp",
+		Short:         "Run a language server over stdio to check and complete the configuration file",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(NoColor)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			server := NewLSPServer("dodo", strings.TrimSpace(Version))
+			if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	return cmd
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lspTestConfig = `version: 2
+project:
+  project_id: project_id
+  name: Test Project
+pages:
+  - type: markdown
+    filepath: docs/a.md
+  - type: match
+    pattern: "docs/*.md"
+  - type: 
+`
+
+// lspMessage frames a JSON-RPC message with the Content-Length header.
+func lspMessage(t *testing.T, message map[string]any) string {
+	t.Helper()
+	message["jsonrpc"] = "2.0"
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func lspPositionParams(uri string, line, character int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": character},
+	}
+}
+
+// serveLSP sends the messages to the server and returns the responses keyed by their ID, and the notifications in order.
+func serveLSP(t *testing.T, messages ...string) (map[string]map[string]any, []map[string]any) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	require.NoError(t, NewLSPServer("dodo", "1.2.3").Serve(strings.NewReader(strings.Join(messages, "")), out))
+
+	responses := map[string]map[string]any{}
+	notifications := []map[string]any{}
+	reader := bufio.NewReader(out)
+	for {
+		body, err := readLSPMessage(reader)
+		if err != nil {
+			break
+		}
+		resp := map[string]any{}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		assert.Equal(t, "2.0", resp["jsonrpc"])
+		if id, ok := resp["id"]; ok {
+			key, _ := json.Marshal(id)
+			responses[string(key)] = resp
+		} else {
+			notifications = append(notifications, resp)
+		}
+	}
+	return responses, notifications
+}
+
+func TestLSPServer(t *testing.T) {
+	dir := prepareUploadProject(t)
+	docs := prepareSubDir(t, dir, "docs")
+	prepareFile(t, docs, "a.md", "---\ntitle: Page A\nlink: page-a\ndescription: The first page\n---\n# A")
+	prepareFile(t, docs, "b.md", "---\ntitle: Page B\nlink: page-b\n---\n# B")
+	uri := lspURIFromPath(filepath.Join(dir, ".dodo.yaml"))
+	markdownURI := lspURIFromPath(filepath.Join(docs, "a.md"))
+
+	brokenConfig := strings.Replace(lspTestConfig, "docs/a.md", "docs/missing.md", 1)
+	responses, notifications := serveLSP(t,
+		lspMessage(t, map[string]any{"id": 1, "method": "initialize", "params": map[string]any{}}),
+		lspMessage(t, map[string]any{"method": "initialized", "params": map[string]any{}}),
+		lspMessage(t, map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": uri, "languageId": "yaml", "version": 1, "text": brokenConfig},
+		}}),
+		lspMessage(t, map[string]any{"method": "textDocument/didChange", "params": map[string]any{
+			"textDocument":   map[string]any{"uri": uri, "version": 2},
+			"contentChanges": []any{map[string]any{"text": lspTestConfig}},
+		}}),
+		lspMessage(t, map[string]any{"id": 2, "method": "textDocument/completion", "params": lspPositionParams(uri, 9, 10)}),
+		lspMessage(t, map[string]any{"id": 3, "method": "textDocument/completion", "params": lspPositionParams(uri, 6, 19)}),
+		lspMessage(t, map[string]any{"id": 4, "method": "textDocument/completion", "params": lspPositionParams(uri, 8, 4)}),
+		lspMessage(t, map[string]any{"id": 5, "method": "textDocument/hover", "params": lspPositionParams(uri, 6, 15)}),
+		lspMessage(t, map[string]any{"id": 6, "method": "textDocument/definition", "params": lspPositionParams(uri, 6, 15)}),
+		lspMessage(t, map[string]any{"id": 7, "method": "textDocument/definition", "params": lspPositionParams(uri, 8, 15)}),
+		lspMessage(t, map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": markdownURI, "languageId": "markdown", "version": 1, "text": "---\ntitle: A\ncreated_at: yesterday\n---\n# A"},
+		}}),
+		lspMessage(t, map[string]any{"id": 8, "method": "textDocument/hover", "params": lspPositionParams(uri, 1, 0)}),
+		lspMessage(t, map[string]any{"id": 9, "method": "unknown/method"}),
+		lspMessage(t, map[string]any{"id": 10, "method": "shutdown"}),
+		lspMessage(t, map[string]any{"method": "exit"}),
+		// Messages after `exit` are not read.
+		lspMessage(t, map[string]any{"id": 11, "method": "shutdown"}),
+	)
+
+	capabilities := responses["1"]["result"].(map[string]any)["capabilities"].(map[string]any)
+	assert.Equal(t, true, capabilities["hoverProvider"])
+	assert.Equal(t, true, capabilities["definitionProvider"])
+
+	// The diagnostics follow the edits.
+	require.Len(t, notifications, 3)
+	diagnostics := notifications[0]["params"].(map[string]any)["diagnostics"].([]any)
+	require.NotEmpty(t, diagnostics)
+	first := diagnostics[0].(map[string]any)
+	assert.Contains(t, first["message"], "docs/missing.md")
+	assert.InEpsilon(t, 5, first["range"].(map[string]any)["start"].(map[string]any)["line"], 0, "the error should point to the page")
+	diagnostics = notifications[1]["params"].(map[string]any)["diagnostics"].([]any)
+	require.Len(t, diagnostics, 1, "only the page being typed should be reported")
+	assert.InEpsilon(t, 9, diagnostics[0].(map[string]any)["range"].(map[string]any)["start"].(map[string]any)["line"], 0)
+	markdownDiagnostics := notifications[2]["params"].(map[string]any)["diagnostics"].([]any)
+	require.Len(t, markdownDiagnostics, 1)
+	assert.Contains(t, markdownDiagnostics[0].(map[string]any)["message"], "created_at")
+
+	labels := func(id string) []string {
+		labels := []string{}
+		for _, item := range responses[id]["result"].([]any) {
+			labels = append(labels, item.(map[string]any)["label"].(string))
+		}
+		return labels
+	}
+	assert.Equal(t, []string{"markdown", "match", "directory", "section", "include"}, labels("2"))
+	assert.Equal(t, []string{"a.md", "b.md"}, labels("3"))
+	pathEdit := responses["3"]["result"].([]any)[0].(map[string]any)["textEdit"].(map[string]any)
+	assert.Equal(t, "docs/a.md", pathEdit["newText"])
+	assert.Equal(t, []string{"pattern", "sort_key", "sort_order"}, labels("4"), "the keys of a match page should be completed")
+
+	hover := responses["5"]["result"].(map[string]any)["contents"].(map[string]any)["value"]
+	assert.Contains(t, hover, "**Page A**")
+	assert.Contains(t, hover, "`page-a`")
+	assert.Contains(t, hover, "The first page")
+
+	locations := responses["6"]["result"].([]any)
+	require.Len(t, locations, 1)
+	assert.Equal(t, markdownURI, locations[0].(map[string]any)["uri"])
+	assert.Len(t, responses["7"]["result"], 2, "the pattern should go to every matched file")
+
+	assert.Nil(t, responses["8"]["result"])
+	assert.Contains(t, responses["8"], "result", "no hover should be null")
+	assert.InEpsilon(t, jsonRPCMethodNotFound, responses["9"]["error"].(map[string]any)["code"], 0)
+	assert.Contains(t, responses["10"], "result")
+	assert.NotContains(t, responses, "11")
+}
+
+func TestLSPServerNotInitialized(t *testing.T) {
+	t.Parallel()
+	responses, _ := serveLSP(t, lspMessage(t, map[string]any{"id": 1, "method": "textDocument/hover", "params": lspPositionParams("file:///.dodo.yaml", 0, 0)}))
+	assert.InEpsilon(t, lspServerNotInitialized, responses["1"]["error"].(map[string]any)["code"], 0)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+type MCPArgs struct {
+	debug    bool   // enable debug mode
+	endpoint string // the endpoint of the dodo API server
+}
+
+// Implement LoggingConfig and PrinterConfig interface for MCPArgs.
+// The logs go to stderr, so that they do not mix with the messages on stdout.
+func (opts *MCPArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *MCPArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *MCPArgs) EnableColor() bool {
+	return false
+}
+
+func (opts *MCPArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateMCPCmd() *cobra.Command {
+	opts := MCPArgs{}
+	cmd := &cobra.Command{
+		Use:           "mcp",
+		Short:         "Run a Model Context Protocol server over stdio to search and read the documents",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(NoColor)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			env := NewEnvArgs()
+			if err := CheckArgsAndEnvForMCP(opts, env); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			if err := mcpCmdEntrypoint(opts, env); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com/", "The endpoint of the dodo API server")
+	return cmd
+}
+
+func CheckArgsAndEnvForMCP(args MCPArgs, env EnvArgs) error {
+	if !env.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if args.endpoint == "" {
+		return errors.New("endpoint is not set")
+	}
+	return nil
+}
+
+func mcpCmdEntrypoint(args MCPArgs, env EnvArgs) error {
+	client, err := NewAPIClient(args.endpoint, env)
+	if err != nil {
+		return err
+	}
+	server := NewMCPServer("dodo", strings.TrimSpace(Version), NewMCPTools(client))
+	return server.Serve(os.Stdin, os.Stdout)
+}
+
+// NewMCPTools returns the tools which search and read the documents on dodo.
+func NewMCPTools(client *api.Client) []MCPTool {
+	return []MCPTool{
+		{
+			Name:        "search",
+			Description: "Search the documents of the projects the user can access. Returns the title, the URL, the project and an excerpt of every matching page.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "query": {"type": "string", "description": "The words to search for"},
+    "projects": {"type": "array", "items": {"type": "string"}, "description": "The IDs of the projects to search. All the projects are searched if omitted"}
+  },
+  "required": ["query"],
+  "additionalProperties": false
+}`),
+			Handler: func(arguments json.RawMessage) (string, error) {
+				return mcpSearch(client, arguments)
+			},
+		},
+		{
+			Name:        "read_document",
+			Description: "Read a page of a project in markdown. The path is the link of the page, as returned by get_layout.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "project_id": {"type": "string", "description": "The project ID (slug)"},
+    "path": {"type": "string", "description": "The path of the page"}
+  },
+  "required": ["project_id", "path"],
+  "additionalProperties": false
+}`),
+			Handler: func(arguments json.RawMessage) (string, error) {
+				return mcpReadDocument(client, arguments)
+			},
+		},
+		{
+			Name:        "list_projects",
+			Description: "List the projects of the organizations the user belongs to.",
+			InputSchema: json.RawMessage(`{"type": "object", "properties": {}, "additionalProperties": false}`),
+			Handler: func(arguments json.RawMessage) (string, error) {
+				return mcpListProjects(client, arguments)
+			},
+		},
+		{
+			Name:        "get_layout",
+			Description: "Get the page tree of a project, with the path of every page that can be passed to read_document.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "project_id": {"type": "string", "description": "The project ID (slug)"}
+  },
+  "required": ["project_id"],
+  "additionalProperties": false
+}`),
+			Handler: func(arguments json.RawMessage) (string, error) {
+				return mcpGetLayout(client, arguments)
+			},
+		},
+	}
+}
+
+func mcpSearch(client *api.Client, arguments json.RawMessage) (string, error) {
+	args := struct {
+		Query    string   `json:"query"`
+		Projects []string `json:"projects"`
+	}{}
+	if err := decodeMCPArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if args.Query == "" {
+		return "", errors.New("`query` is required")
+	}
+	records, err := sendSearchRequest(client, args.Query, args.Projects)
+	if err != nil {
+		return "", err
+	}
+	return marshalMCPText(records)
+}
+
+func mcpReadDocument(client *api.Client, arguments json.RawMessage) (string, error) {
+	args := struct {
+		ProjectID string `json:"project_id"`
+		Path      string `json:"path"`
+	}{}
+	if err := decodeMCPArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if args.ProjectID == "" || args.Path == "" {
+		return "", errors.New("`project_id` and `path` are required")
+	}
+	return sendReadDocumentRequest(client, args.ProjectID, args.Path)
+}
+
+func mcpListProjects(client *api.Client, arguments json.RawMessage) (string, error) {
+	if err := decodeMCPArguments(arguments, &struct{}{}); err != nil {
+		return "", err
+	}
+	projects, err := NewProjectFromAPI(client)
+	if err != nil {
+		return "", err
+	}
+	outputs := make([]DocOutput, 0, len(projects))
+	for _, p := range projects {
+		outputs = append(outputs, DocOutput{
+			Slug:        p.Slug,
+			ProjectName: p.ProjectName,
+			IsPublic:    p.IsPublic,
+			ProjectID:   p.ProjectID,
+			URL:         p.BaseURL,
+		})
+	}
+	return marshalMCPText(outputs)
+}
+
+func mcpGetLayout(client *api.Client, arguments json.RawMessage) (string, error) {
+	args := struct {
+		ProjectID string `json:"project_id"`
+	}{}
+	if err := decodeMCPArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if args.ProjectID == "" {
+		return "", errors.New("`project_id` is required")
+	}
+	layout, err := NewLayoutFromAPI(client, args.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	return marshalMCPText(LayoutOutput{Layout: layout, Links: layout.Links()})
+}
+
+func marshalMCPText(v any) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the output: %w", err)
+	}
+	return string(b), nil
+}
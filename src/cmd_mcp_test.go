@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/api"
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+func newMCPTestServer(t *testing.T) (*MCPServer, *openapi.SearchPostRequest) {
+	t.Helper()
+	searched := &openapi.SearchPostRequest{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /search/v1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(searched)                                                                                                                   //nolint:errcheck
+		w.Write([]byte(`{"status": "ok", "records": [{"id": "1", "title": "Guide", "url": "https://myproject.example.com/guide", "project_slug": "myproject"}]}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /document/v1/myproject/guide", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "markdown": "# Guide"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /layout/v1/myproject", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(testLayoutResponse)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /projects/v1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "projects": [{"slug": "myproject", "project_name": "My Project", "project_id": "p1"}]}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewAPIClient(server.URL, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+	return NewMCPServer("dodo", "1.2.3", NewMCPTools(client)), searched
+}
+
+// serveMCP sends the messages to the server and returns the responses keyed by their ID.
+func serveMCP(t *testing.T, server *MCPServer, messages ...string) map[string]map[string]any {
+	t.Helper()
+	out := &bytes.Buffer{}
+	require.NoError(t, server.Serve(strings.NewReader(strings.Join(messages, "\n")+"\n"), out))
+
+	responses := map[string]map[string]any{}
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		resp := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &resp), "every response should be a JSON object on a line")
+		assert.Equal(t, "2.0", resp["jsonrpc"])
+		id, _ := json.Marshal(resp["id"])
+		responses[string(id)] = resp
+	}
+	return responses
+}
+
+func toolText(t *testing.T, resp map[string]any) (string, bool) {
+	t.Helper()
+	result, ok := resp["result"].(map[string]any)
+	require.True(t, ok, "the response should have a result: %v", resp)
+	content, ok := result["content"].([]any)
+	require.True(t, ok)
+	require.Len(t, content, 1)
+	text, ok := content[0].(map[string]any)["text"].(string)
+	require.True(t, ok)
+	isError, _ := result["isError"].(bool)
+	return text, isError
+}
+
+func TestMCPInitializeAndList(t *testing.T) {
+	t.Parallel()
+	server, _ := newMCPTestServer(t)
+	responses := serveMCP(t, server,
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05", "capabilities": {}, "clientInfo": {"name": "test", "version": "0"}}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`,
+		`{"jsonrpc": "2.0", "id": "3", "method": "ping"}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "resources/list"}`,
+		`not json`,
+	)
+	require.Len(t, responses, 5, "the notification should not be answered")
+
+	result := responses["1"]["result"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, "2024-11-05", result["protocolVersion"])
+	assert.Equal(t, map[string]any{"name": "dodo", "version": "1.2.3"}, result["serverInfo"])
+
+	tools := responses["2"]["result"].(map[string]any)["tools"].([]any) //nolint:forcetypeassert
+	names := []string{}
+	for _, tool := range tools {
+		names = append(names, tool.(map[string]any)["name"].(string)) //nolint:forcetypeassert
+		assert.Contains(t, tool, "inputSchema")
+	}
+	assert.Equal(t, []string{"search", "read_document", "list_projects", "get_layout"}, names)
+
+	assert.Equal(t, map[string]any{}, responses[`"3"`]["result"])
+	assert.InDelta(t, jsonRPCMethodNotFound, responses["4"]["error"].(map[string]any)["code"], 0) //nolint:forcetypeassert
+	assert.InDelta(t, jsonRPCParseError, responses["null"]["error"].(map[string]any)["code"], 0)  //nolint:forcetypeassert
+}
+
+func TestMCPTools(t *testing.T) {
+	t.Parallel()
+	server, searched := newMCPTestServer(t)
+	responses := serveMCP(t, server,
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "search", "arguments": {"query": "guide", "projects": ["p1"]}}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "read_document", "arguments": {"project_id": "myproject", "path": "guide"}}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "list_projects"}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "get_layout", "arguments": {"project_id": "myproject"}}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "tools/call", "params": {"name": "read_document", "arguments": {"project_id": "myproject", "path": "missing"}}}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "tools/call", "params": {"name": "search", "arguments": {}}}`,
+	)
+
+	text, isError := toolText(t, responses["1"])
+	assert.False(t, isError)
+	assert.Contains(t, text, `"title": "Guide"`)
+	assert.Equal(t, openapi.SearchPostRequest{Query: "guide", Projects: []string{"p1"}}, *searched)
+
+	text, isError = toolText(t, responses["2"])
+	assert.False(t, isError)
+	assert.Equal(t, "# Guide", text)
+
+	text, _ = toolText(t, responses["3"])
+	assert.Contains(t, text, `"project_name": "My Project"`)
+
+	text, _ = toolText(t, responses["4"])
+	assert.Contains(t, text, `"path": "/api/search"`)
+
+	// Failures are reported to the model with the hint.
+	text, isError = toolText(t, responses["5"])
+	assert.True(t, isError)
+	assert.Contains(t, text, "404")
+	assert.Contains(t, text, errorHint(&api.Error{StatusCode: http.StatusNotFound}))
+
+	text, isError = toolText(t, responses["6"])
+	assert.True(t, isError)
+	assert.Contains(t, text, "`query` is required")
+}
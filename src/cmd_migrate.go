@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/config"
+	"github.com/toritoritori29/dodo-cli/src/utils"
+)
+
+// The `migrate` command converts a version 1 config into version 2.
+// The config is written only when both versions produce the same page tree.
+
+type MigrateArgs struct {
+	configPath string // config file path
+	output     string // path to write the version 2 config. Defaults to configPath
+	dryRun     bool   // print the version 2 config instead of writing it
+	debug      bool   // enable debug mode
+	noColor    bool   // disable color output
+}
+
+// Implement LoggingConfig and PrinterConfig interface for MigrateArgs.
+func (opts *MigrateArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *MigrateArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *MigrateArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *MigrateArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateMigrateCmd() *cobra.Command {
+	opts := MigrateArgs{}
+	cmd := &cobra.Command{
+		Use:           "migrate",
+		Short:         "Convert a version 1 configuration file to version 2",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			if err := CheckArgsForMigrate(opts); err != nil {
+				return printer.HandleError(err)
+			}
+			printer = NewPrinterFromArgs(&opts)
+			if err := migrateCmdEntrypoint(opts); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.configPath, "config", "c", ".dodo.yaml", "Path to the configuration file")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Path to write the version 2 configuration file. Defaults to the path of --config")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the version 2 configuration instead of writing it")
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	return cmd
+}
+
+func CheckArgsForMigrate(args MigrateArgs) error {
+	if _, err := os.Stat(args.configPath); err != nil {
+		return fmt.Errorf("specified `config` argument is invalid. Please check if the file exists. Path: %s", args.configPath)
+	}
+	return nil
+}
+
+// MigrationReport summarizes the result of `dodo migrate`.
+type MigrationReport struct {
+	Output   string   // path of the version 2 config. Empty in dry-run mode
+	Contents []byte   // the version 2 config
+	Pages    int      // number of pages, which is the same in both versions
+	Notes    []string // changes which need the attention of the user
+}
+
+func migrateCmdEntrypoint(args MigrateArgs) error {
+	report, err := executeMigrate(args)
+	if err != nil {
+		return err
+	}
+	if args.dryRun {
+		if _, err := os.Stdout.Write(report.Contents); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	printMigrationReport(report, args)
+	return nil
+}
+
+func executeMigrate(args MigrateArgs) (*MigrationReport, error) {
+	contents, err := os.ReadFile(args.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the config file: %w", err)
+	}
+	version, err := config.DetectConfigVersion(bytes.NewReader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect the config version: %w", err)
+	}
+	if version != 1 {
+		return nil, fmt.Errorf("only version 1 configs can be migrated. %s is version %d", args.configPath, version)
+	}
+
+	// Build the page tree of the current config to compare it with the migrated one.
+	confV1, err := config.ParseConfigV1(config.NewParseStateV1(args.configPath, "./"), bytes.NewReader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config file: %w", err)
+	}
+	metadataV1, err := NewMetadataFromConfigV1(confV1)
+	if err != nil {
+		return nil, err
+	}
+
+	migration, err := config.MigrateConfigV1ToV2(config.NewParseStateV1(args.configPath, "./"), bytes.NewReader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate the config file: %w", err)
+	}
+	output := args.output
+	if output == "" {
+		output = args.configPath
+	}
+	confV2, err := config.ParseConfigV2(config.NewParseStateV2(output, "./"), bytes.NewReader(migration.Contents))
+	if err != nil {
+		return nil, fmt.Errorf("the migrated config is invalid: %w", err)
+	}
+	metadataV2, err := NewMetadataFromConfigV2(confV2)
+	if err != nil {
+		return nil, err
+	}
+	if err := comparePageTrees(&metadataV1.Page, &metadataV2.Page); err != nil {
+		return nil, err
+	}
+
+	report := &MigrationReport{
+		Contents: migration.Contents,
+		Pages:    metadataV1.Page.Count(),
+		Notes:    migration.Notes,
+	}
+	if args.dryRun {
+		return report, nil
+	}
+	if err := utils.WriteFileKeepingMode(output, migration.Contents); err != nil {
+		return nil, fmt.Errorf("failed to write the config file: %w", err)
+	}
+	report.Output = output
+	return report, nil
+}
+
+// comparePageTrees checks that two page trees have the same pages in the same order.
+// The location of the markdown files is not compared, because version 1 keeps the absolute paths of the files found by `match`.
+// The hash of the contents is compared instead.
+func comparePageTrees(expected, actual *Page) error {
+	expectedLines := expected.TreeLines(false)
+	actualLines := actual.TreeLines(false)
+	for i := range max(len(expectedLines), len(actualLines)) {
+		want, got := "(none)", "(none)"
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if want != got {
+			return fmt.Errorf("the migrated config does not produce the same pages.\n  version 1: %s\n  version 2: %s",
+				strings.TrimSpace(want), strings.TrimSpace(got))
+		}
+	}
+	return nil
+}
+
+func printMigrationReport(report *MigrationReport, args MigrateArgs) {
+	if report.Output != "" {
+		log.Infof("migrated %s to version 2 and wrote %s", args.configPath, report.Output)
+	} else {
+		log.Infof("%s can be migrated to version 2. Nothing is written in dry-run mode", args.configPath)
+	}
+	log.Infof("both versions produce the same %d pages", report.Pages)
+	if len(report.Notes) == 0 {
+		return
+	}
+	log.Infof("please review the following changes:")
+	log.IncreasePadding()
+	for _, note := range report.Notes {
+		log.Info(note)
+	}
+	log.DecreasePadding()
+}
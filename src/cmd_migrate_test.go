@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const migrateTestConfig = `version: 1
+project:
+  project_id: "project_id"
+  name: "Test Project"
+pages:
+  # The first page
+  - markdown: "page1.md"
+    title: "Page 1"
+    path: "page1"
+  - directory: "Guide"
+    children:
+      - match: "guide/*.md"
+        sort_key: "created_at"
+`
+
+func prepareMigrateProject(t *testing.T) {
+	t.Helper()
+	dir := prepareUploadProject(t)
+	prepareFile(t, dir, ".dodo.yaml", migrateTestConfig)
+	guide := prepareSubDir(t, dir, "guide")
+	prepareFile(t, guide, "a.md", "---\ntitle: A\nlink: a\ncreated_at: 2025-01-01T00:00:00Z\n---\n# A")
+	prepareFile(t, guide, "b.md", "---\ntitle: B\nlink: b\ncreated_at: 2024-01-01T00:00:00Z\n---\n# B")
+}
+
+func TestExecuteMigrate(t *testing.T) {
+	prepareMigrateProject(t)
+
+	// Dry-run mode does not touch the config.
+	report, err := executeMigrate(MigrateArgs{configPath: ".dodo.yaml", dryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.Output)
+	assert.Equal(t, 4, report.Pages)
+	assert.Contains(t, string(report.Contents), "version: 2")
+	contents, err := os.ReadFile(".dodo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, migrateTestConfig, string(contents))
+
+	report, err = executeMigrate(MigrateArgs{configPath: ".dodo.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, ".dodo.yaml", report.Output)
+	assert.Len(t, report.Notes, 1, "the match sorted by date should be expanded")
+
+	expected := `version: 2
+project:
+  project_id: project_id
+  name: Test Project
+pages:
+  # The first page
+  - type: markdown
+    filepath: page1.md
+    title: Page 1
+    link: page1
+  - type: directory
+    title: Guide
+    children:
+      - type: markdown
+        filepath: guide/b.md
+      - type: markdown
+        filepath: guide/a.md
+`
+	contents, err = os.ReadFile(".dodo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(contents))
+
+	// The migrated config is used as is by the other commands.
+	metadata, err := loadMetadataFromConfig(".dodo.yaml", "")
+	require.NoError(t, err)
+	assert.Equal(t, 4, metadata.Page.Count())
+
+	_, err = executeMigrate(MigrateArgs{configPath: ".dodo.yaml"})
+	require.ErrorContains(t, err, "only version 1 configs can be migrated")
+}
+
+func TestExecuteMigrateOutput(t *testing.T) {
+	prepareMigrateProject(t)
+
+	report, err := executeMigrate(MigrateArgs{configPath: ".dodo.yaml", output: filepath.Join("guide", "v2.yaml")})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("guide", "v2.yaml"), report.Output)
+
+	contents, err := os.ReadFile(".dodo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, migrateTestConfig, string(contents), "the original config should be kept")
+	_, err = os.Stat(filepath.Join("guide", "v2.yaml"))
+	require.NoError(t, err)
+}
+
+func TestComparePageTrees(t *testing.T) {
+	t.Parallel()
+	leaf := func(title, hash string) Page {
+		return Page{Type: PageTypeLeafNode, Language: []PageLanguageWiseInfo{{Language: "en", Title: title, Path: "p", Hash: hash}}}
+	}
+	v1 := Page{Type: PageTypeRootNode, Children: []Page{leaf("A", "1"), leaf("B", "2")}}
+
+	same := Page{Type: PageTypeRootNode, Children: []Page{leaf("A", "1"), leaf("B", "2")}}
+	same.Children[0].Language[0].Filepath = "/abs/a.md"
+	require.NoError(t, comparePageTrees(&v1, &same), "the location of the files should be ignored")
+
+	swapped := Page{Type: PageTypeRootNode, Children: []Page{leaf("B", "2"), leaf("A", "1")}}
+	require.ErrorContains(t, comparePageTrees(&v1, &swapped), "Title: A")
+
+	missing := Page{Type: PageTypeRootNode, Children: []Page{leaf("A", "1")}}
+	require.ErrorContains(t, comparePageTrees(&v1, &missing), "version 2: (none)")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caarlos0/log"
+	"github.com/spf13/cobra"
+)
+
+type PackArgs struct {
+	file      string // config file path
+	output    string // the path to write the archive file
+	debug     bool   // enable debug mode
+	noColor   bool   // disable color output
+	projectID string // override project_id from config
+}
+
+// Implement LoggingConfig and PrinterConfig interface for PackArgs.
+func (opts *PackArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *PackArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *PackArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *PackArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreatePackCmd() *cobra.Command {
+	opts := PackArgs{}
+	cmd := &cobra.Command{
+		Use:           "pack",
+		Short:         "build a self-contained archive of the project to upload later with `upload --archive`",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			if err := CheckArgsForPack(opts); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			if err := packCmdEntrypoint(opts); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.file, "config", "c", ".dodo.yaml", "Path to the configuration file")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", DefaultArchivePath, "Path to write the archive file")
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Override the project_id from the config file")
+	return cmd
+}
+
+func CheckArgsForPack(args PackArgs) error {
+	// Check if `file` is valid
+	_, err := os.Stat(args.file)
+	if err != nil && os.IsNotExist(err) {
+		return fmt.Errorf("specified `file` argument is invalid. Please check if the file exists. Path: %s", args.file)
+	}
+	if err != nil {
+		return fmt.Errorf("specified `file` argument is invalid. Path: %s", args.file)
+	}
+
+	// Check if the output path is valid
+	parentDir := filepath.Dir(args.output)
+	_, err = os.Stat(parentDir)
+	if err != nil && os.IsNotExist(err) {
+		return fmt.Errorf("specified output path is invalid. Please check if the parent directory exists. Path: %s", args.output)
+	}
+	if err != nil {
+		return fmt.Errorf("the provided output path is invalid. Path: %s", args.output)
+	}
+	return nil
+}
+
+func packCmdEntrypoint(args PackArgs) error {
+	metadata, err := loadMetadataFromConfig(args.file, args.projectID)
+	if err != nil {
+		return err
+	}
+
+	// Build the archive next to the output and move it there on success,
+	// so that a failed build does not leave a truncated archive at the output path.
+	archive, err := NewTemporaryArchive(filepath.Dir(args.output))
+	if err != nil {
+		return err
+	}
+	defer archive.Close() //nolint:errcheck
+	if merr := archive.Archive(metadata); merr != nil {
+		return merr
+	}
+	if err := archive.SaveAs(args.output); err != nil {
+		return err
+	}
+	log.Infof("the archive is written to %s", args.output)
+	log.Infof("upload it with `dodo upload --archive %s`", args.output)
+	return nil
+}
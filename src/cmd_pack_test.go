@@ -0,0 +1,234 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackAndUploadArchive(t *testing.T) {
+	dir := prepareUploadProject(t)
+	output := filepath.Join(t.TempDir(), "site.zip")
+	require.NoError(t, packCmdEntrypoint(PackArgs{file: ".dodo.yaml", output: output, projectID: "packed"}))
+
+	// Upload in another "job" where the source tree does not exist.
+	for _, name := range []string{".dodo.yaml", "page1.md", "page2.md"} {
+		require.NoError(t, os.Remove(filepath.Join(dir, name)))
+	}
+	server := newFakeServer(t)
+	args := UploadArgs{
+		file:     ".dodo.yaml",
+		archive:  output,
+		endpoint: server.UploadURL(),
+		format:   FormatText,
+		rootPath: ".",
+	}
+	require.NoError(t, CheckArgsAndEnv(args, EnvArgs{APIKey: "test-token"}))
+	url, err := executeUpload(args, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/docs/packed", url)
+	assert.Len(t, server.Received(0), 2)
+
+	// The archive is not removed after the upload.
+	_, err = os.Stat(output)
+	require.NoError(t, err)
+}
+
+func TestTemporaryArchiveSaveAs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	output := filepath.Join(dir, "site.zip")
+	require.NoError(t, os.WriteFile(output, []byte("previous archive"), 0o600))
+	prepareFile(t, dir, "page.md", "# Page")
+	metadata := &Metadata{Page: Page{Type: PageTypeRootNode}}
+
+	// A failed build leaves neither a truncated archive nor a temporary file.
+	archive, err := NewTemporaryArchive(dir)
+	require.NoError(t, err)
+	require.NotNil(t, archive.ArchiveBlobs(metadata, []MetadataBlob{{Hash: "missing", Filepath: filepath.Join(dir, "missing.md")}}))
+	require.NoError(t, archive.Close())
+	contents, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "previous archive", string(contents))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "only the previous archive and the page should remain")
+
+	// A successful build replaces the output.
+	archive, err = NewTemporaryArchive(dir)
+	require.NoError(t, err)
+	require.Nil(t, archive.ArchiveBlobs(metadata, []MetadataBlob{{Hash: "page", Filepath: filepath.Join(dir, "page.md")}}))
+	require.NoError(t, archive.SaveAs(output))
+	require.NoError(t, archive.Close())
+	reader, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer reader.Close() //nolint:errcheck
+	assert.Len(t, reader.File, 2)
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestOpenArchive(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	writeZip := func(name string, files map[string]string) string {
+		path := filepath.Join(dir, name)
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		defer file.Close() //nolint:errcheck
+		writer := zip.NewWriter(file)
+		for name, content := range files {
+			w, err := writer.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		return path
+	}
+	metadata := `{"version":"1","project":{"project_id":"id"},"page":{"type":"RootNode","updated_at":"","children":[
+		{"type":"LeafNode","updated_at":"","language":[{"language":"en","hash":"abc","filepath":"page.md"}]}]}}`
+
+	t.Run("valid archive", func(t *testing.T) {
+		t.Parallel()
+		archive, err := OpenArchive(writeZip("valid.zip", map[string]string{
+			MetadataFileName: metadata,
+			"blobs/abc":      "# Page",
+		}))
+		require.NoError(t, err)
+		defer archive.Close() //nolint:errcheck
+		assert.Equal(t, "id", archive.Metadata.Project.ProjectID)
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		t.Parallel()
+		_, err := OpenArchive(writeZip("no_metadata.zip", map[string]string{"blobs/abc": "# Page"}))
+		require.ErrorContains(t, err, "metadata.json is not found")
+	})
+
+	t.Run("missing blob", func(t *testing.T) {
+		t.Parallel()
+		_, err := OpenArchive(writeZip("no_blob.zip", map[string]string{MetadataFileName: metadata}))
+		require.ErrorContains(t, err, "the blob of page.md is not found")
+	})
+
+	t.Run("not a zip file", func(t *testing.T) {
+		t.Parallel()
+		prepareFile(t, dir, "broken.zip", "not a zip")
+		_, err := OpenArchive(filepath.Join(dir, "broken.zip"))
+		require.Error(t, err)
+	})
+}
+
+func TestCheckArchiveArgs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, "site.zip", "")
+	archive := filepath.Join(dir, "site.zip")
+
+	require.NoError(t, checkArchiveArgs(UploadArgs{archive: archive}))
+	require.Error(t, checkArchiveArgs(UploadArgs{archive: filepath.Join(dir, "missing.zip")}))
+	require.Error(t, checkArchiveArgs(UploadArgs{archive: archive, incremental: true}))
+	require.Error(t, checkArchiveArgs(UploadArgs{archive: archive, watch: true}))
+}
+
+const deterministicTestConfig = `version: 2
+project:
+  project_id: "project_id"
+  name: "Test Project"
+  default_language: "ja"
+pages:
+  - type: section
+    lang:
+      en:
+        title: "Guide"
+      fr:
+        title: "Guide FR"
+      ja:
+        title: "ガイド"
+    children:
+      - type: match
+        pattern: "guide/*.md"
+assets:
+  - "assets/*"
+`
+
+// prepareDeterministicProject creates a multi-language project whose match statement has no sort key.
+func prepareDeterministicProject(t *testing.T) {
+	t.Helper()
+	dir := prepareUploadProject(t)
+	prepareFile(t, dir, ".dodo.yaml", deterministicTestConfig)
+	guide := prepareSubDir(t, dir, "guide")
+	for _, group := range []string{"alpha", "beta", "gamma", "delta"} {
+		for _, lang := range []string{"en", "fr", "ja"} {
+			content := "---\ntitle: " + group + " " + lang + "\nlink: " + group + "_" + lang +
+				"\nlang: " + lang + "\ngroup: " + group + "\n---\n# " + group
+			prepareFile(t, guide, group+"."+lang+".md", content)
+		}
+	}
+	assets := prepareSubDir(t, dir, "assets")
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		prepareFile(t, assets, name, "\x89PNG\r\n\x1a\n"+name)
+	}
+}
+
+func TestPackIsDeterministic(t *testing.T) {
+	prepareDeterministicProject(t)
+	output := t.TempDir()
+
+	var firstMetadata, firstArchive []byte
+	for i := range 5 {
+		metadata, err := loadMetadataFromConfig(".dodo.yaml", "")
+		require.NoError(t, err)
+		serialized, err := metadata.Serialize()
+		require.NoError(t, err)
+
+		path := filepath.Join(output, "dodo.zip")
+		require.NoError(t, packCmdEntrypoint(PackArgs{file: ".dodo.yaml", output: path}))
+		archive, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		if i == 0 {
+			firstMetadata, firstArchive = serialized, archive
+			continue
+		}
+		assert.Equal(t, string(firstMetadata), string(serialized), "metadata.json should be byte-identical")
+		assert.Equal(t, firstArchive, archive, "the archive should be byte-identical")
+	}
+
+	// The default language comes first, then the others in alphabetical order.
+	metadata, err := loadMetadataFromConfig(".dodo.yaml", "")
+	require.NoError(t, err)
+	section := metadata.Page.Children[0]
+	languages := make([]string, 0, len(section.Language))
+	for _, lang := range section.Language {
+		languages = append(languages, lang.Language)
+	}
+	assert.Equal(t, []string{"ja", "en", "fr"}, languages)
+
+	// Pages without a sort key follow the file name order.
+	links := make([]string, 0, len(section.Children))
+	for _, child := range section.Children {
+		links = append(links, child.Language[0].Path)
+	}
+	assert.Equal(t, []string{"alpha_ja", "beta_ja", "delta_ja", "gamma_ja"}, links)
+}
+
+func TestPackHasFixedTimestamps(t *testing.T) {
+	prepareUploadProject(t)
+	output := filepath.Join(t.TempDir(), "dodo.zip")
+	require.NoError(t, packCmdEntrypoint(PackArgs{file: ".dodo.yaml", output: output}))
+
+	reader, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer reader.Close() //nolint:errcheck
+	for _, f := range reader.File {
+		assert.Equal(t, 1980, f.Modified.Year(), f.Name)
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+
 	"github.com/caarlos0/log"
 	"github.com/spf13/cobra"
 )
@@ -12,7 +16,7 @@ func CreatePreviewCmd() *cobra.Command {
 		"upload the project to dodo-doc preview environment",
 		"https://api.dodo-doc.com/project/upload/demo",
 		&opts,
-		func(_ *cobra.Command, _ []string) error {
+		func(cmd *cobra.Command, _ []string) error {
 			printer := NewErrorPrinter(ErrorLevel)
 			if err := InitLogger(&opts); err != nil {
 				return printer.HandleError(err)
@@ -25,6 +29,11 @@ func CreatePreviewCmd() *cobra.Command {
 
 			printer = NewPrinterFromArgs(&opts)
 			jsonWriter := NewJSONWriterFromArgs(opts)
+			if opts.watch {
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+				defer stop()
+				return watchPreviewCmd(ctx, opts, env, jsonWriter, printer)
+			}
 			if err := previewCmdEntrypoint(opts, env, jsonWriter); err != nil {
 				jsonWriter.ShowFailedJSONText(err)
 				return printer.HandleError(err)
@@ -32,6 +41,7 @@ func CreatePreviewCmd() *cobra.Command {
 			return nil
 		},
 	)
+	cmd.Flags().BoolVar(&opts.watch, "watch", false, "Watch the project files and re-upload the preview on changes")
 	return cmd
 }
 
@@ -41,6 +51,10 @@ func previewCmdEntrypoint(args UploadArgs, env EnvArgs, jsonWriter *JSONWriter)
 		return err
 	}
 
+	if args.dryRun {
+		return dryRunUpload(args, jsonWriter)
+	}
+
 	// Execute the upload operation.
 	url, err := executeUpload(args, env)
 	if err != nil {
@@ -51,3 +65,21 @@ func previewCmdEntrypoint(args UploadArgs, env EnvArgs, jsonWriter *JSONWriter)
 	jsonWriter.ShowSucceededJSONText(url)
 	return nil
 }
+
+// watchPreviewCmd uploads the preview once and then again every time the project files change.
+// Upload errors are reported but do not stop the watcher.
+func watchPreviewCmd(ctx context.Context, args UploadArgs, env EnvArgs, jsonWriter *JSONWriter, printer *ErrorPrinter) error {
+	runPreview := func() {
+		if err := previewCmdEntrypoint(args, env, jsonWriter); err != nil {
+			jsonWriter.ShowFailedJSONText(err)
+			printer.HandleError(err) //nolint:errcheck
+		}
+	}
+
+	runPreview()
+	watcher := NewWatcher(args.file)
+	return watcher.Run(ctx, func() {
+		log.Infof("change detected. re-uploading the preview")
+		runPreview()
+	})
+}
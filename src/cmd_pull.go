@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/api"
+	"github.com/toritoritori29/dodo-cli/src/config"
+)
+
+type PullArgs struct {
+	projectID string // the project ID (slug) of the deployed project
+	dir       string // the directory to write the project to
+	force     bool   // overwrite the existing files
+	debug     bool   // enable debug mode
+	noColor   bool   // disable color output
+	endpoint  string // the endpoint of the dodo API server
+}
+
+// Implement LoggingConfig and PrinterConfig interface for PullArgs.
+func (opts *PullArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *PullArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *PullArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *PullArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreatePullCmd() *cobra.Command {
+	opts := PullArgs{}
+	cmd := &cobra.Command{
+		Use:           "pull",
+		Short:         "Export a deployed project to markdown files and a .dodo.yaml",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			env := NewEnvArgs()
+			if err := CheckArgsAndEnvForPull(opts, env); err != nil {
+				return printer.HandleError(err)
+			}
+
+			printer = NewPrinterFromArgs(&opts)
+			if err := pullCmdEntrypoint(opts, env); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.projectID, "project-id", "s", "", "The project ID (slug) of the deployed project")
+	cmd.Flags().StringVarP(&opts.dir, "dir", "d", ".", "The directory to write the project to")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite the existing files in the directory")
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	cmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com/", "The endpoint of the dodo API server")
+	return cmd
+}
+
+func CheckArgsAndEnvForPull(args PullArgs, env EnvArgs) error {
+	if !env.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if args.projectID == "" {
+		return errors.New("project ID is required. Please set --project-id")
+	}
+	if args.dir == "" {
+		return errors.New("the output directory is required. Please set --dir")
+	}
+	configPath := filepath.Join(args.dir, ".dodo.yaml")
+	if _, err := os.Stat(configPath); err == nil && !args.force {
+		return fmt.Errorf("%s already exists. Use --force to overwrite it", configPath)
+	}
+	return nil
+}
+
+func pullCmdEntrypoint(args PullArgs, env EnvArgs) error {
+	conf, err := executePull(args, env)
+	if err != nil {
+		return err
+	}
+	log.Infof("pulled %d pages and %d assets into %s", countPulledPages(conf.Pages), len(conf.Assets), args.dir)
+	return nil
+}
+
+// executePull writes the markdown files, the assets and `.dodo.yaml` of the deployed project into args.dir.
+func executePull(args PullArgs, env EnvArgs) (*PulledConfig, error) {
+	client, err := NewAPIClient(args.endpoint, env)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Sending request to the %s", client.LayoutURL(args.projectID))
+	layout, err := NewLayoutFromAPI(client, args.projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(args.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the directory %s: %w", args.dir, err)
+	}
+
+	puller := &projectPuller{
+		client:      client,
+		projectID:   args.projectID,
+		dir:         args.dir,
+		force:       args.force,
+		defaultLang: layoutDefaultLanguage(&layout.Page),
+		files:       map[string]struct{}{},
+		assets:      map[string]struct{}{},
+	}
+	pages, err := puller.pullPages(layout.Page.Children)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, errors.New("the project has no pages")
+	}
+
+	conf := &PulledConfig{
+		Version: 2,
+		Project: PulledProject{
+			ProjectID:       args.projectID,
+			Name:            pulledProjectName(client, args.projectID),
+			DefaultLanguage: puller.defaultLang,
+		},
+		Pages:  pages,
+		Assets: puller.assetOrder,
+	}
+	if err := writePulledConfig(conf, args.dir); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// writePulledConfig writes `.dodo.yaml` and checks that it can be parsed back.
+func writePulledConfig(conf *PulledConfig, dir string) error {
+	b, err := conf.Serialize()
+	if err != nil {
+		return err
+	}
+	configPath := filepath.Join(dir, ".dodo.yaml")
+	if err := os.WriteFile(configPath, b, 0o644); err != nil { //nolint:mnd
+		return fmt.Errorf("failed to write %s: %w", configPath, err)
+	}
+	log.Debugf("wrote %s", configPath)
+
+	state := config.NewParseStateV2(configPath, dir)
+	if _, err := config.ParseConfigV2(state, bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("the generated config file is invalid: %w", err)
+	}
+	return nil
+}
+
+// pulledProjectName looks up the name of the project. The project ID is used if it cannot be found.
+func pulledProjectName(client *api.Client, projectID string) string {
+	projects, err := NewProjectFromAPI(client)
+	if err != nil {
+		log.Warnf("failed to fetch the project name: %v", err)
+		return projectID
+	}
+	for _, p := range projects {
+		if p.Slug == projectID && p.ProjectName != "" {
+			return p.ProjectName
+		}
+	}
+	return projectID
+}
+
+// layoutDefaultLanguage returns the first language of the tree. The default language is always listed first.
+func layoutDefaultLanguage(root *Page) string {
+	if len(root.Language) > 0 {
+		return root.Language[0].Language
+	}
+	for i := range root.Children {
+		if lang := layoutDefaultLanguage(&root.Children[i]); lang != "" {
+			return lang
+		}
+	}
+	if root.Type == PageTypeRootNode {
+		return config.SystemDefaultLanguageV2
+	}
+	return ""
+}
+
+type projectPuller struct {
+	client      *api.Client
+	projectID   string
+	dir         string
+	force       bool
+	defaultLang string
+	files       map[string]struct{} // the markdown files written so far
+	assets      map[string]struct{}
+	assetOrder  []string
+}
+
+func (p *projectPuller) pullPages(pages []Page) ([]PulledPage, error) {
+	result := make([]PulledPage, 0, len(pages))
+	for i := range pages {
+		pulled, err := p.pullPage(&pages[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pulled...)
+	}
+	return result, nil
+}
+
+func (p *projectPuller) pullPage(page *Page) ([]PulledPage, error) {
+	children, err := p.pullPages(page.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	switch page.Type {
+	case PageTypeSectionNode:
+		return []PulledPage{{Type: config.ConfigPageTypeSectionV2, Lang: pulledTitles(page), Children: children}}, nil
+	case PageTypeDirNode:
+		if len(children) == 0 {
+			log.Warnf("skipped the directory %s, as it has no pages", pageTitle(page))
+			return nil, nil
+		}
+		return []PulledPage{{Type: config.ConfigPageTypeDirectoryV2, Lang: pulledTitles(page), Children: children}}, nil
+	case PageTypeDirNodeWithPage:
+		// Version 2 has no directory with its own page. The page becomes the first child of the directory.
+		markdown, err := p.pullMarkdown(page)
+		if err != nil {
+			return nil, err
+		}
+		return []PulledPage{{Type: config.ConfigPageTypeDirectoryV2, Lang: pulledTitles(page), Children: append([]PulledPage{*markdown}, children...)}}, nil
+	default:
+		markdown, err := p.pullMarkdown(page)
+		if err != nil {
+			return nil, err
+		}
+		return append([]PulledPage{*markdown}, children...), nil
+	}
+}
+
+// pullMarkdown downloads the page in every language and writes it with the front matter.
+func (p *projectPuller) pullMarkdown(page *Page) (*PulledPage, error) {
+	group := ""
+	if len(page.Language) > 0 {
+		group = pulledGroupID(p.projectID, page.Language[0].Path)
+	}
+
+	lang := yaml.MapSlice{}
+	for _, l := range page.Language {
+		if l.Path == "" {
+			continue
+		}
+		log.Debugf("Fetching the page %s", l.Path)
+		markdown, err := sendReadDocumentRequest(p.client, p.projectID, l.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the page %s: %w", l.Path, err)
+		}
+		// Remove the front matter, if any, as `dodo pull` writes its own.
+		contents, err := config.ReadMarkdownBodyFrom(strings.NewReader(markdown))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the page %s: %w", l.Path, err)
+		}
+		body := string(contents)
+
+		matter := config.FrontMatter{
+			Title:           l.Title,
+			Link:            l.Path,
+			LanguageGroupID: group,
+			Description:     l.Description,
+			UpdatedAt:       page.UpdatedAt,
+			UnknownTags:     map[string]interface{}{"lang": l.Language},
+		}
+		name, err := p.writeFile(pulledMarkdownPath(l.Path, l.Language, p.defaultLang), []byte(matter.String()+body))
+		if err != nil {
+			return nil, err
+		}
+		if err := p.pullAssets(body); err != nil {
+			return nil, err
+		}
+		lang = append(lang, yaml.MapItem{Key: l.Language, Value: pulledLangPage{Filepath: name}})
+	}
+	if len(lang) == 0 {
+		return nil, fmt.Errorf("the page %s has no link", pageTitle(page))
+	}
+	return &PulledPage{Type: config.ConfigPageTypeMarkdownV2, Lang: lang}, nil
+}
+
+func (p *projectPuller) pullAssets(markdown string) error {
+	for _, ref := range listAssetReferences(markdown) {
+		if _, ok := p.assets[ref]; ok {
+			continue
+		}
+		p.assets[ref] = struct{}{}
+
+		log.Debugf("Fetching the asset %s", ref)
+		data, err := p.client.GetAsset(p.projectID, ref)
+		if errors.Is(err, api.ErrNotFound) {
+			log.Warnf("skipped the asset %s, as it is not found on the server", ref)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch the asset %s: %w", ref, err)
+		}
+		target := filepath.Join(p.dir, filepath.FromSlash(ref))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create the directory for %s: %w", ref, err)
+		}
+		if err := p.checkOverwrite(target); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil { //nolint:mnd
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		p.assetOrder = append(p.assetOrder, ref)
+	}
+	return nil
+}
+
+// writeFile writes a markdown file under the directory. A suffix is added if the name is already taken.
+// It returns the path relative to the directory.
+func (p *projectPuller) writeFile(name string, contents []byte) (string, error) {
+	base := strings.TrimSuffix(name, ".md")
+	for i := 2; ; i++ {
+		if _, ok := p.files[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s-%d.md", base, i)
+	}
+	p.files[name] = struct{}{}
+
+	target := filepath.Join(p.dir, name)
+	if err := p.checkOverwrite(target); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(target, contents, 0o644); err != nil { //nolint:mnd
+		return "", fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	log.Debugf("wrote %s", target)
+	return name, nil
+}
+
+func (p *projectPuller) checkOverwrite(target string) error {
+	if p.force {
+		return nil
+	}
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s already exists. Use --force to overwrite it", target)
+	}
+	return nil
+}
+
+func pulledTitles(page *Page) yaml.MapSlice {
+	titles := yaml.MapSlice{}
+	for _, l := range page.Language {
+		titles = append(titles, yaml.MapItem{Key: l.Language, Value: pulledLangTitle{Title: l.Title, Description: l.Description}})
+	}
+	return titles
+}
+
+func pageTitle(page *Page) string {
+	if len(page.Language) > 0 {
+		return page.Language[0].Title
+	}
+	return page.Type
+}
+
+func countPulledPages(pages []PulledPage) int {
+	count := 0
+	for _, p := range pages {
+		if p.Type == config.ConfigPageTypeMarkdownV2 {
+			count += len(p.Lang)
+		}
+		count += countPulledPages(p.Children)
+	}
+	return count
+}
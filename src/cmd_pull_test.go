@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+const testPullLayout = `{"status": "ok", "is_public": true, "allowed_orgs": [], "layout": {
+  "type": "RootNode",
+  "children": [
+    {"type": "LeafNode", "language": [
+      {"language": "en", "title": "Intro", "path": "intro"},
+      {"language": "ja", "title": "Intro JA", "path": "intro_ja"}
+    ], "children": []},
+    {"type": "SectionNode", "language": [{"language": "en", "title": "Guides"}, {"language": "ja", "title": "Guides JA"}], "children": [
+      {"type": "LeafNode", "language": [{"language": "en", "title": "Upload", "path": "guides/upload"}], "children": []}
+    ]}
+  ]
+}}`
+
+func newPullServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	documents := map[string]string{
+		"intro":         "---\ntitle: Old\n---\n# Intro\n![logo](assets/logo.png)\n![remote](https://example.com/a.png)\n",
+		"intro_ja":      "# Intro JA\n![logo](/assets/logo.png)\n",
+		"guides/upload": "# Upload\n",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /layout/v1/my-project", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(testPullLayout)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /document/v1/my-project/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		markdown, ok := documents[r.PathValue("path")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(openapi.DocumentGetResponse{Status: openapi.Ok, Markdown: &markdown}) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /asset/v1/my-project/assets/logo.png", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("png")) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /projects/v1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "projects": [{"slug": "my-project", "project_name": "My Project"}]}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPullRoundTrip(t *testing.T) {
+	server := newPullServer(t)
+	dir := t.TempDir()
+	args := PullArgs{projectID: "my-project", dir: dir, endpoint: server.URL}
+	conf, err := executePull(args, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+	assert.Equal(t, "My Project", conf.Project.Name)
+	assert.Equal(t, "en", conf.Project.DefaultLanguage)
+	assert.Equal(t, []string{"assets/logo.png"}, conf.Assets)
+
+	intro, err := os.ReadFile(filepath.Join(dir, "intro.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(intro), "title: \"Intro\"\nlink: \"intro\"\n")
+	assert.Contains(t, string(intro), "lang: en\n")
+	assert.NotContains(t, string(intro), "title: Old", "the front matter from the server should be replaced")
+	asset, err := os.ReadFile(filepath.Join(dir, "assets", "logo.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(asset))
+
+	// The generated project is parsed like any other project.
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(oldWd) }) //nolint:errcheck
+	metadata, err := loadMetadataFromConfig(".dodo.yaml", "")
+	require.NoError(t, err)
+
+	layout := &Layout{ProjectID: "my-project", Page: metadata.Page}
+	assert.Equal(t, []LayoutLink{
+		{Language: "en", Title: "Intro", Path: "intro", Depth: 0},
+		{Language: "ja", Title: "Intro JA", Path: "intro_ja", Depth: 0},
+		{Language: "en", Title: "Upload", Path: "guides/upload", Depth: 1},
+	}, layout.Links())
+	require.Len(t, metadata.Asset, 1)
+	assert.Equal(t, "assets/logo.png", metadata.Asset[0].Path)
+}
+
+func TestPullRefusesToOverwrite(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, ".dodo.yaml", "version: 2\n")
+	args := PullArgs{projectID: "my-project", dir: dir}
+	env := EnvArgs{APIKey: "test-token"}
+	require.ErrorContains(t, CheckArgsAndEnvForPull(args, env), "already exists")
+
+	args.force = true
+	require.NoError(t, CheckArgsAndEnvForPull(args, env))
+}
+
+func TestListAssetReferences(t *testing.T) {
+	t.Parallel()
+	markdown := "![a](img/a.png) ![b](<img/b c.png>) ![c](https://example.com/c.png) ![d](../d.png) ![e](/img/a.png?v=1) ![f](data:image/png;base64,xx)"
+	assert.Equal(t, []string{"img/a.png", "img/b c.png"}, listAssetReferences(markdown))
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/caarlos0/log"
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
 )
 
+var AvailableReadFormat = []string{ //nolint: gochecknoglobals
+	FormatText,
+	FormatJSON,
+}
+
 type ReadArgs struct {
 	projectID string
 	path      string
@@ -16,11 +26,14 @@ type ReadArgs struct {
 	noColor   bool
 	endpoint  string
 	url       string
+	format    string // output format (text, json)
+	render    bool   // render the markdown for the terminal and show it in a pager
+	lang      string // language of the document
 }
 
 // Implement LoggingConfig and PrinterConfig interface for PreviewArgs.
 func (opts *ReadArgs) DisableLogging() bool {
-	return false
+	return opts.format == FormatJSON
 }
 
 func (opts *ReadArgs) EnableDebugMode() bool {
@@ -63,47 +76,128 @@ func CreateReadCmd() *cobra.Command {
 	readCmd.Flags().StringVarP(&opts.projectID, "project-id", "s", "", "The project ID (slug) to read the document from")
 	readCmd.Flags().StringVarP(&opts.path, "path", "p", "", "The path of the document to read")
 	readCmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com/", "Server endpoint for search")
+	readCmd.Flags().StringVarP(&opts.format, "format", "f", FormatText, fmt.Sprintf("Output format. Available values: [%s]", strings.Join(AvailableReadFormat, ", ")))
+	readCmd.Flags().BoolVar(&opts.render, "render", false, "Render the markdown for the terminal and show it in a pager")
+	readCmd.Flags().StringVar(&opts.lang, "lang", "", "The language of the document to read, e.g. ja. The path of the page in any language can be given")
 	return readCmd
 }
 
-func CheckArgsAndEnvForRead(_ ReadArgs, env *EnvArgs) error {
+func CheckArgsAndEnvForRead(args ReadArgs, env *EnvArgs) error {
 	if !env.IsAuthenticated() {
-		return errors.New("not authenticated. Please run 'dodo login' or set the DODO_API_KEY environment variable")
+		return ErrNotAuthenticated
+	}
+	if !slices.Contains(AvailableReadFormat, args.format) {
+		return fmt.Errorf("unknown format: %s", args.format)
+	}
+	if args.format == FormatJSON && args.render {
+		return errors.New("--render is not supported in json format")
+	}
+	if args.format == FormatJSON && args.debug {
+		return errors.New("debug mode is not supported in json format")
 	}
 	return nil
 }
 
+// ReadOutput is the output of `dodo read --format json`.
+type ReadOutput struct {
+	ProjectID   string         `json:"project_id"`
+	Path        string         `json:"path"`
+	IsPublic    bool           `json:"is_public"`
+	AllowedOrgs []string       `json:"allowed_orgs"`
+	Page        map[string]any `json:"page"`
+	Markdown    string         `json:"markdown"`
+}
+
 func readCmdEntrypoint(args *ReadArgs, env *EnvArgs) error {
 	if err := InitLogger(args); err != nil {
 		return err
 	}
 
-	endpoint, err := NewEndpoint(args.endpoint)
+	output, err := executeRead(args, env)
 	if err != nil {
 		return err
 	}
 
+	// The pager needs a terminal. The document is rendered without colors when the output is piped.
+	if args.render && term.IsTerminal(os.Stdout.Fd()) {
+		width, _, err := term.GetSize(os.Stdout.Fd())
+		if err != nil || width > MaxWidth {
+			width = MaxWidth
+		}
+		rendered, err := RenderMarkdown(output.Markdown, MarkdownStyle(!args.noColor), width)
+		if err != nil {
+			return err
+		}
+		return RunPager(fmt.Sprintf("%s %s", output.ProjectID, output.Path), rendered)
+	}
+	return writeReadOutput(args, output, os.Stdout)
+}
+
+func executeRead(args *ReadArgs, env *EnvArgs) (*ReadOutput, error) {
+	client, err := NewAPIClient(args.endpoint, *env)
+	if err != nil {
+		return nil, err
+	}
+
 	projectID := args.projectID
 	path := args.path
 	if args.url != "" {
-		projectID, path, err = parseURL(args.url)
+		projectID, path, err = resolveDocumentURL(client, args.url)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	if projectID == "" {
-		return errors.New("project ID is required")
+		return nil, errors.New("project ID is required")
 	}
 	if path == "" {
-		return errors.New("path is required")
+		return nil, errors.New("path is required")
+	}
+	if args.lang != "" {
+		layout, err := NewLayoutFromAPI(client, projectID)
+		if err != nil {
+			return nil, err
+		}
+		path, err = layout.LanguagePath(path, args.lang)
+		if err != nil {
+			return nil, err
+		}
 	}
 	log.Debugf("Reading document from project %s, path %s", projectID, path)
 
-	content, err := sendReadDocumentRequest(env, endpoint, projectID, path)
+	data, err := client.GetDocument(projectID, path)
 	if err != nil {
-		return err
+		return nil, err //nolint:wrapcheck
+	}
+	return &ReadOutput{
+		ProjectID:   projectID,
+		Path:        path,
+		IsPublic:    data.IsPublic,
+		AllowedOrgs: data.AllowedOrgs,
+		Page:        data.Page,
+		Markdown:    *data.Markdown,
+	}, nil
+}
+
+func writeReadOutput(args *ReadArgs, output *ReadOutput, w io.Writer) error {
+	var text string
+	switch {
+	case args.format == FormatJSON:
+		b, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the output: %w", err)
+		}
+		text = string(b) + "\n"
+	case args.render:
+		rendered, err := RenderMarkdown(output.Markdown, MarkdownStyle(false), MaxWidth)
+		if err != nil {
+			return err
+		}
+		text = rendered
+	default:
+		text = output.Markdown + "\n"
 	}
-	if _, err := fmt.Fprintf(os.Stdout, "%s\n", content); err != nil {
+	if _, err := io.WriteString(w, text); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 	return nil
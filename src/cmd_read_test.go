@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+func newReadServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /layout/v1/myproject", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(testLayoutResponse)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /projects/v1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "projects": []}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /document/v1/myproject/ja/guide", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "is_public": true, "allowed_orgs": ["org-1"], "page": {"title": "Guide JA"}, "markdown": "# Guide JA\n\nHello"}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteRead(t *testing.T) {
+	t.Parallel()
+	server := newReadServer(t)
+	env := &EnvArgs{APIKey: "test-token"}
+
+	t.Run("json with lang", func(t *testing.T) {
+		t.Parallel()
+		args := &ReadArgs{projectID: "myproject", path: "/guide", lang: "ja", format: FormatJSON, endpoint: server.URL}
+		output, err := executeRead(args, env)
+		require.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, writeReadOutput(args, output, out))
+		actual := ReadOutput{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+		assert.Equal(t, ReadOutput{
+			ProjectID:   "myproject",
+			Path:        "/ja/guide",
+			IsPublic:    true,
+			AllowedOrgs: []string{"org-1"},
+			Page:        map[string]any{"title": "Guide JA"},
+			Markdown:    "# Guide JA\n\nHello",
+		}, actual)
+	})
+
+	t.Run("render without terminal", func(t *testing.T) {
+		t.Parallel()
+		args := &ReadArgs{projectID: "myproject", path: "/ja/guide", render: true, format: FormatText, endpoint: server.URL}
+		output, err := executeRead(args, env)
+		require.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, writeReadOutput(args, output, out))
+		assert.Contains(t, out.String(), "Guide JA")
+		assert.Contains(t, out.String(), "Hello")
+		assert.NotContains(t, out.String(), "\x1b[", "no escape sequence should be written to a pipe")
+	})
+
+	t.Run("url with lang", func(t *testing.T) {
+		t.Parallel()
+		args := &ReadArgs{url: "https://myproject.org.dodo-doc.com/guide#section", lang: "ja", format: FormatText, endpoint: server.URL}
+		output, err := executeRead(args, env)
+		require.NoError(t, err)
+		assert.Equal(t, "/ja/guide", output.Path)
+	})
+
+	t.Run("unknown lang", func(t *testing.T) {
+		t.Parallel()
+		args := &ReadArgs{projectID: "myproject", path: "/guide", lang: "fr", format: FormatText, endpoint: server.URL}
+		_, err := executeRead(args, env)
+		require.ErrorContains(t, err, "Available languages: en, ja")
+	})
+}
+
+func TestCheckArgsAndEnvForRead(t *testing.T) {
+	t.Parallel()
+	env := &EnvArgs{APIKey: "test-token"}
+	require.NoError(t, CheckArgsAndEnvForRead(ReadArgs{format: FormatText, render: true}, env))
+	require.Error(t, CheckArgsAndEnvForRead(ReadArgs{format: FormatJSON, render: true}, env))
+	require.Error(t, CheckArgsAndEnvForRead(ReadArgs{format: FormatTUI}, env))
+	require.ErrorIs(t, CheckArgsAndEnvForRead(ReadArgs{format: FormatText}, &EnvArgs{}), ErrNotAuthenticated)
+}
+
+func TestResolveDocumentURL(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /projects/v1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "projects": [
+			{"slug": "custom", "project_id": "p1", "base_url": "https://docs.example.com"},
+			{"slug": "local", "project_id": "p2", "base_url": "http://localhost:8080/docs/"},
+			{"slug": "local-api", "project_id": "p3", "base_url": "http://localhost:8080/docs/api"}
+		]}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := NewAPIClient(server.URL, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		url  string
+		slug string
+		path string
+	}{
+		{"https://docs.example.com/guide", "custom", "/guide"},
+		{"https://DOCS.example.com/ja/guide/#install", "custom", "/ja/guide"},
+		{"http://localhost:8080/docs/guide?tab=1", "local", "/guide"},
+		{"localhost:8080/docs/ja/guide", "local", "/ja/guide"},
+		{"http://localhost:8080/docs/api/search", "local-api", "/search"},
+		{"http://localhost:8080/docs/apis", "local", "/apis"},
+		{"https://legacy.org.dodo-doc.com/guide#top", "legacy", "/guide"},
+	}
+	for _, test := range tests {
+		slug, path, err := resolveDocumentURL(client, test.url)
+		require.NoError(t, err, test.url)
+		assert.Equal(t, test.slug, slug, test.url)
+		assert.Equal(t, test.path, path, test.url)
+	}
+
+	_, _, err = resolveDocumentURL(client, "http://localhost:9090/docs/guide")
+	require.ErrorContains(t, err, "does not belong to any of your projects")
+}
+
+func TestResolveDocumentURLWithoutProjects(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /projects/v1", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"status": "error"}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := NewAPIClient(server.URL, EnvArgs{APIKey: "test-token"}, api.WithRetry(api.NewRetryPolicy(0)))
+	require.NoError(t, err)
+
+	// The URL on the default domain does not need the projects.
+	slug, path, err := resolveDocumentURL(client, "https://legacy.org.dodo-doc.com/ja/guide")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", slug)
+	assert.Equal(t, "/ja/guide", path)
+
+	_, _, err = resolveDocumentURL(client, "https://docs.example.com/guide")
+	require.ErrorContains(t, err, "failed to list the projects")
+}
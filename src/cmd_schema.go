@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/config"
+)
+
+// The `schema` command prints the JSON Schema of `.dodo.yaml` for editors.
+
+type SchemaArgs struct {
+	configVersion int  // version of the config format. 0 means both versions
+	debug         bool // enable debug mode
+	noColor       bool // disable color output
+}
+
+// Implement LoggingConfig and PrinterConfig interface for SchemaArgs.
+func (opts *SchemaArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *SchemaArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *SchemaArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *SchemaArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateSchemaCmd() *cobra.Command {
+	opts := SchemaArgs{}
+	cmd := &cobra.Command{
+		Use:           "schema",
+		Short:         "Print the JSON Schema of the configuration file",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			if err := CheckArgsForSchema(opts); err != nil {
+				return printer.HandleError(err)
+			}
+			printer = NewPrinterFromArgs(&opts)
+			if err := executeSchema(opts, os.Stdout); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&opts.configVersion, "config-version", 0, "The version of the configuration file. Both versions are accepted if not set")
+	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	return cmd
+}
+
+func CheckArgsForSchema(args SchemaArgs) error {
+	if args.configVersion < 0 || args.configVersion > 2 {
+		return fmt.Errorf("unsupported config version: %d. Available values: [1, 2]", args.configVersion)
+	}
+	return nil
+}
+
+func executeSchema(args SchemaArgs, w io.Writer) error {
+	schema, err := config.NewJSONSchema(args.configVersion)
+	if err != nil {
+		return fmt.Errorf("failed to build the schema: %w", err)
+	}
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the schema: %w", err)
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/config"
+)
+
+func TestExecuteSchema(t *testing.T) {
+	t.Parallel()
+	out := &bytes.Buffer{}
+	require.NoError(t, executeSchema(SchemaArgs{configVersion: 2}, out))
+
+	schema := map[string]any{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+	assert.Equal(t, config.JSONSchemaDraft, schema["$schema"])
+	assert.Contains(t, schema["definitions"], "pageV2")
+}
+
+func TestCheckArgsForSchema(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, CheckArgsForSchema(SchemaArgs{}))
+	require.NoError(t, CheckArgsForSchema(SchemaArgs{configVersion: 1}))
+	require.Error(t, CheckArgsForSchema(SchemaArgs{configVersion: 3}))
+}
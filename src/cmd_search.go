@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,15 +10,21 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strings"
 
+	"github.com/atotto/clipboard"
+	"github.com/caarlos0/log"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/api"
+	"github.com/toritoritori29/dodo-cli/src/config"
 	"github.com/toritoritori29/dodo-cli/src/openapi"
 )
 
@@ -33,11 +40,19 @@ const (
 	MaxWidth          = 100
 )
 
+var AvailableSearchFormat = []string{ //nolint: gochecknoglobals
+	FormatTUI,
+	FormatJSON,
+	FormatPlain,
+}
+
 type SearchArgs struct {
 	query    []string // search query
+	projects []string // project IDs to search. Empty means all the projects
+	limit    int      // maximum number of results. 0 means no limit
 	endpoint string   // server endpoint to search
 	debug    bool     // enable debug mode
-	format   string   // output format (tui, json)
+	format   string   // output format (tui, json, plain)
 }
 
 // Implement LoggingConfig and PrinterConfig interface for UploadArgs.
@@ -62,11 +77,17 @@ func CreateSearchCmd() *cobra.Command {
 	searchCmd := &cobra.Command{
 		Use:   "search",
 		Short: "Search for a string in the project files.",
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// The TUI needs a terminal. Print the results as plain text when the output is piped.
+			if !cmd.Flags().Changed("format") && !term.IsTerminal(os.Stdout.Fd()) {
+				opts.format = FormatPlain
+			}
+
 			printer := NewErrorPrinter(ErrorLevel)
 			if err := InitLogger(&opts); err != nil {
 				return printer.HandleError(err)
 			}
+			opts.projects = resolveSearchProjects(opts.projects, ".dodo.yaml")
 			env := NewEnvArgs()
 			err := CheckArgsAndEnvForSearch(opts, env)
 			if err != nil {
@@ -79,30 +100,72 @@ func CreateSearchCmd() *cobra.Command {
 			return nil
 		},
 	}
-	searchCmd.Flags().StringArrayVarP(&opts.query, "query", "q", nil, "Search query (for json output only)")
+	searchCmd.Flags().StringArrayVarP(&opts.query, "query", "q", nil, "Search query (for json and plain output only)")
+	searchCmd.Flags().StringArrayVar(&opts.projects, "project", nil, "Project ID to search. Can be repeated. Defaults to the project_id of .dodo.yaml in the current directory")
+	searchCmd.Flags().IntVar(&opts.limit, "limit", 0, "Maximum number of results. 0 means no limit")
 	searchCmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode")
 	searchCmd.Flags().StringVar(&opts.endpoint, "endpoint", "https://contents.dodo-doc.com", "Server endpoint for search")
-	searchCmd.Flags().StringVar(&opts.format, "format", FormatTUI, "Output format (tui, json)")
+	searchCmd.Flags().StringVar(&opts.format, "format", FormatTUI, fmt.Sprintf("Output format. Available values: [%s]", strings.Join(AvailableSearchFormat, ", ")))
 	return searchCmd
 }
 
+// resolveSearchProjects returns the projects given by the flag.
+// If none is given, it falls back to the project of the config file, so that the search is scoped to the project being edited.
+func resolveSearchProjects(projects []string, configPath string) []string {
+	if len(projects) > 0 {
+		return projects
+	}
+	if _, err := os.Stat(configPath); err != nil {
+		return nil
+	}
+	// Only the project is read. Building the pages would read every markdown file on each search.
+	projectID, err := readProjectID(configPath)
+	if err != nil {
+		log.Warnf("Searching all the projects because %s could not be read: %s", configPath, err)
+		return nil
+	}
+	log.Debugf("Searching the project %s of %s", projectID, configPath)
+	return []string{projectID}
+}
+
+func readProjectID(configPath string) (string, error) {
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the config file: %w", err)
+	}
+	version, err := config.DetectConfigVersion(bytes.NewReader(contents))
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+	if version != 1 && version != 2 {
+		return "", fmt.Errorf("unsupported config version: %d", version)
+	}
+	return config.DetectProjectID(bytes.NewReader(contents)) //nolint:wrapcheck
+}
+
 func CheckArgsAndEnvForSearch(args SearchArgs, env EnvArgs) error {
 	if !env.IsAuthenticated() {
-		return errors.New("not authenticated. Please run 'dodo login' or set the DODO_API_KEY environment variable")
+		return ErrNotAuthenticated
 	}
 	if args.endpoint == "" {
 		return errors.New("no endpoint provided")
 	}
-	if args.format != FormatTUI && args.format != FormatJSON {
+	if !slices.Contains(AvailableSearchFormat, args.format) {
 		return fmt.Errorf("unknown format: %s", args.format)
 	}
+	if args.limit < 0 {
+		return fmt.Errorf("limit must not be negative: %d", args.limit)
+	}
 
 	// TUI
 	if args.format == FormatTUI && args.query != nil {
 		return errors.New("you cannot provide query arguments in TUI mode")
 	}
 
-	// JSON
+	// JSON and plain
+	if args.format != FormatTUI && len(args.query) == 0 {
+		return errors.New("no query provided. Use --query to specify the search query")
+	}
 	if args.format == FormatJSON && args.debug {
 		return errors.New("debug mode is not supported in json format")
 	}
@@ -113,38 +176,59 @@ func searchCmdEntrypoint(args SearchArgs, env EnvArgs) error {
 	switch args.format {
 	case FormatTUI:
 		return executeSearchTUI(args, env)
-	case FormatJSON:
-		return executeSearchJSON(args, env)
+	case FormatJSON, FormatPlain:
+		return executeSearch(args, env, os.Stdout)
 	default:
 		return fmt.Errorf("unknown format: %s", args.format)
 	}
 }
 
-// JSON implementation.
-func executeSearchJSON(args SearchArgs, env EnvArgs) error {
-	endpoint, err := NewEndpoint(args.endpoint)
+// JSON and plain implementation.
+func executeSearch(args SearchArgs, env EnvArgs, w io.Writer) error {
+	client, err := NewAPIClient(args.endpoint, env)
 	if err != nil {
-		return fmt.Errorf("invalid endpoint: %w", err)
+		return err
 	}
 	query := strings.Join(args.query, " ")
-	records, err := sendSearchRequest(&env, endpoint, query)
+	records, err := sendSearchRequest(client, query, args.projects)
 	if err != nil {
 		return fmt.Errorf("failed to execute the search: %w", err)
 	}
+	records = limitSearchRecords(records, args.limit)
 
-	output := openapi.SearchPostResponse{
-		Records: records,
-	}
-	outputBytes, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal the search response: %w", err)
+	var output string
+	if args.format == FormatJSON {
+		outputBytes, err := json.MarshalIndent(openapi.SearchPostResponse{Records: records}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the search response: %w", err)
+		}
+		output = string(outputBytes) + "\n"
+	} else {
+		output = searchRecordsPlain(records)
 	}
-	if _, err := fmt.Fprintf(os.Stdout, "%s\n", string(outputBytes)); err != nil {
+	if _, err := io.WriteString(w, output); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 	return nil
 }
 
+func limitSearchRecords(records []openapi.SearchRecord, limit int) []openapi.SearchRecord {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
+
+// searchRecordsPlain prints a record per line as `<project slug>\t<title>\t<url>`, so that the output can be processed with cut, awk or fzf.
+func searchRecordsPlain(records []openapi.SearchRecord) string {
+	field := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+	var sb strings.Builder
+	for _, record := range records {
+		fmt.Fprintf(&sb, "%s\t%s\t%s\n", field.Replace(record.ProjectSlug), field.Replace(record.Title), field.Replace(record.Url))
+	}
+	return sb.String()
+}
+
 // TUI implementation.
 func executeSearchTUI(args SearchArgs, env EnvArgs) error {
 	model, err := initialModel(args, env)
@@ -163,6 +247,7 @@ type searchItem struct {
 	description string
 	url         string
 	domain      string
+	projectSlug string
 }
 
 func (i searchItem) FilterValue() string { return i.title }
@@ -171,6 +256,13 @@ func (i searchItem) Title() string { return i.title }
 
 func (i searchItem) Description() string { return i.description }
 
+// previewMsg delivers the document fetched for the preview pane.
+type previewMsg struct {
+	url      string
+	markdown string
+	err      error
+}
+
 type model struct {
 	textInput       textinput.Model
 	textInputActive bool
@@ -178,18 +270,29 @@ type model struct {
 	choices         []list.Item
 	selected        map[int]struct{}
 	errorMessage    string
+	statusMessage   string
+
+	// The preview pane shows the selected document next to the list.
+	preview        PagerModel
+	previewOpen    bool
+	previewFocused bool
+	previewItem    searchItem
+	previewRaw     string // markdown of the document, rendered again when the window is resized
+
+	width  int
+	height int
 
 	// configurations
-	endpoint   Endpoint
-	args       *SearchArgs
-	envArgs    *EnvArgs
-	listStyles list.Styles
+	client        *api.Client
+	args          *SearchArgs
+	listStyles    list.Styles
+	markdownStyle string
 }
 
 func initialModel(args SearchArgs, env EnvArgs) (model, error) {
-	endpoint, err := NewEndpoint(args.endpoint)
+	client, err := NewAPIClient(args.endpoint, env)
 	if err != nil {
-		return model{}, fmt.Errorf("invalid endpoint: %w", err)
+		return model{}, err
 	}
 	listStyles := initListStyles()
 
@@ -213,18 +316,21 @@ func initialModel(args SearchArgs, env EnvArgs) (model, error) {
 	l.SetShowHelp(false)
 	l.DisableQuitKeybindings()
 
-	return model{
+	m := model{
 		textInput:       ti,
 		textInputActive: true,
 		list:            l,
 		choices:         items,
 		selected:        make(map[int]struct{}),
 		errorMessage:    "",
-		endpoint:        endpoint,
-		envArgs:         &env,
+		preview:         NewPagerModel(w, h-4),
+		client:          client,
 		args:            &args,
 		listStyles:      listStyles,
-	}, nil
+		markdownStyle:   MarkdownStyle(true),
+	}
+	m.resize(w, h)
+	return m, nil
 }
 
 func (m model) Init() tea.Cmd {
@@ -232,11 +338,22 @@ func (m model) Init() tea.Cmd {
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
-	if msg, ok := msg.(tea.KeyMsg); ok {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.renderPreview()
+		return m, nil
+	case previewMsg:
+		return m.updatePreview(msg)
+	case tea.KeyMsg:
 		m.errorMessage = ""
+		m.statusMessage = ""
 		if msg.Type == tea.KeyCtrlC {
 			return m, tea.Quit
 		}
+		if m.previewFocused {
+			return m.updatePreviewKey(msg)
+		}
 		if msg.Type == tea.KeyEnter {
 			return m.updateEnter(msg)
 		}
@@ -252,6 +369,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 		case "down":
 			return m.updateKeyUpDown(msg)
 		}
+		if !m.textInputActive {
+			if updated, cmd, ok := m.updateItemKey(msg); ok {
+				return updated, cmd
+			}
+		}
 	}
 
 	// Update both textInput and list
@@ -262,6 +384,62 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 	return m, tea.Batch(cmd, listCmd)
 }
 
+// updateItemKey handles the keys on the selected item while the list is focused.
+func (m model) updateItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) { //nolint: ireturn
+	selectedItem, ok := m.list.SelectedItem().(searchItem)
+	if !ok {
+		return m, nil, false
+	}
+	switch msg.String() {
+	case "o":
+		if err := openBrowser(selectedItem.url); err != nil {
+			m.errorMessage = fmt.Sprintf("Failed to open the browser: %s", err)
+		}
+		return m, nil, true
+	case "y":
+		m.copyURL(selectedItem.url)
+		return m, nil, true
+	case "tab", "right":
+		if m.previewOpen {
+			m.previewFocused = true
+			m.list.SetDelegate(newSearchItemDelegate(false))
+		}
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// updatePreviewKey handles the keys while the preview pane is focused.
+func (m model) updatePreviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nolint: ireturn
+	if !m.preview.Finding() {
+		switch msg.String() {
+		case "esc", "tab", "left", "q":
+			m.previewFocused = false
+			m.list.SetDelegate(newSearchItemDelegate(true))
+			return m, nil
+		case "o":
+			if err := openBrowser(m.previewItem.url); err != nil {
+				m.errorMessage = fmt.Sprintf("Failed to open the browser: %s", err)
+			}
+			return m, nil
+		case "y":
+			m.copyURL(m.previewItem.url)
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.preview, cmd = m.preview.Update(msg)
+	return m, cmd
+}
+
+func (m *model) copyURL(url string) {
+	if err := copyToClipboard(url); err != nil {
+		m.errorMessage = fmt.Sprintf("Failed to copy the URL: %s", err)
+		return
+	}
+	m.statusMessage = "Copied " + url
+}
+
 func (m model) updateKeyUpDown(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 	if m.textInputActive {
 		m.textInputActive = false
@@ -293,11 +471,7 @@ func (m model) updateEnter(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 			m.errorMessage = "No item is selected"
 			return m, nil
 		}
-		err := openBrowser(selectedItem.url)
-		if err != nil {
-			m.errorMessage = fmt.Sprintf("Failed to open the browser: %s", err)
-		}
-		return m, nil
+		return m.openPreview(selectedItem)
 	}
 
 	// In case the text input is active.
@@ -305,10 +479,11 @@ func (m model) updateEnter(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 	m.textInput.Blur()
 	query := m.textInput.Value()
 
-	records, err := sendSearchRequest(m.envArgs, m.endpoint, query)
+	records, err := sendSearchRequest(m.client, query, m.args.projects)
 	if err != nil {
 		m.errorMessage = fmt.Sprintf("Failed to execute the search: %s", err)
 	}
+	records = limitSearchRecords(records, m.args.limit)
 
 	// Update the list
 	items := make([]list.Item, len(records))
@@ -327,6 +502,7 @@ func (m model) updateEnter(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 			description: record.Contents,
 			url:         record.Url,
 			domain:      domain,
+			projectSlug: record.ProjectSlug,
 		}
 	}
 	m.list.SetItems(items)
@@ -340,17 +516,116 @@ func (m model) updateEnter(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
 	return m, tea.Batch(cmd, listCmd)
 }
 
+// openPreview splits the screen and fetches the document of the item in the background.
+func (m model) openPreview(item searchItem) (tea.Model, tea.Cmd) { //nolint: ireturn
+	if !m.previewOpen {
+		m.previewOpen = true
+		m.resize(m.width, m.height)
+	}
+	m.previewItem = item
+	m.previewRaw = ""
+	m.preview.SetContent("Loading " + item.url + " ...")
+	return m, fetchPreview(m.client, item)
+}
+
+func fetchPreview(client *api.Client, item searchItem) tea.Cmd {
+	return func() tea.Msg {
+		slug, path, err := searchItemDocument(client, item)
+		if err != nil {
+			return previewMsg{url: item.url, err: err}
+		}
+		markdown, err := sendReadDocumentRequest(client, slug, path)
+		return previewMsg{url: item.url, markdown: markdown, err: err}
+	}
+}
+
+// searchItemDocument returns the project and the path of the document to read.
+func searchItemDocument(client *api.Client, item searchItem) (string, string, error) {
+	if item.projectSlug == "" {
+		return resolveDocumentURL(client, item.url)
+	}
+	u, err := url.Parse(item.url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse the document URL: %w", err)
+	}
+	return item.projectSlug, u.Path, nil
+}
+
+func (m model) updatePreview(msg previewMsg) (tea.Model, tea.Cmd) { //nolint: ireturn
+	// The user may have selected another item while the document was loading.
+	if msg.url != m.previewItem.url {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.preview.SetContent(fmt.Sprintf("Failed to read the document: %s", msg.err))
+		return m, nil
+	}
+	m.previewRaw = msg.markdown
+	m.renderPreview()
+	m.previewFocused = true
+	m.list.SetDelegate(newSearchItemDelegate(false))
+	return m, nil
+}
+
+// renderPreview renders the markdown of the preview for the current width of the pane.
+func (m *model) renderPreview() {
+	if m.previewRaw == "" {
+		return
+	}
+	rendered, err := RenderMarkdown(m.previewRaw, m.markdownStyle, m.previewWidth())
+	if err != nil {
+		// Show the markdown as is rather than nothing.
+		rendered = m.previewRaw
+	}
+	m.preview.SetContent(rendered)
+}
+
+// resize lays out the list and the preview pane. The preview takes 60% of the width when it is open.
+func (m *model) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.textInput.Width = width
+	listWidth := width - 2
+	if m.previewOpen {
+		listWidth = width * 2 / 5
+	}
+	m.list.SetSize(listWidth, max(height-4, 1))
+	m.preview.SetSize(m.previewWidth(), max(height-4, 1))
+}
+
+func (m model) previewWidth() int {
+	return max(m.width-m.width*2/5-3, 1)
+}
+
 func (m model) View() string {
 	searchStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.AdaptiveColor{Light: PrimaryColorLight, Dark: PrimaryColorDark}).
 		Bold(true)
 	text := fmt.Sprintf("%s %s\n\n", searchStyle.Render("Search >"), m.textInput.View())
-	text += m.list.View() + "\n"
+	if m.previewOpen {
+		borderColor := lipgloss.AdaptiveColor{Light: TextColorLightDim, Dark: TextColorDarkDim}
+		if m.previewFocused {
+			borderColor = lipgloss.AdaptiveColor{Light: PrimaryColorLight, Dark: PrimaryColorDark}
+		}
+		pane := lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(borderColor).
+			PaddingLeft(1).
+			Render(m.preview.View())
+		text += lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(m.list.Width()).Render(m.list.View()), pane) + "\n"
+	} else {
+		text += m.list.View() + "\n"
+	}
 
-	if m.errorMessage != "" {
+	switch {
+	case m.errorMessage != "":
 		text += m.listStyles.StatusBar.Render(fmt.Sprintf("Error: %s\n", m.errorMessage))
-	} else {
-		text += m.listStyles.StatusBar.Render(fmt.Sprintln("Press Enter to search, ↑/↓ to navigate, / to focus search field, and Ctrl-C exit."))
+	case m.statusMessage != "":
+		text += m.listStyles.StatusBar.Render(m.statusMessage + "\n")
+	case m.previewFocused:
+		text += m.listStyles.StatusBar.Render(fmt.Sprintln("↑/↓ to scroll, / to find, y to copy the URL, o to open in the browser, Esc to go back to the list."))
+	default:
+		text += m.listStyles.StatusBar.Render(fmt.Sprintln("Press Enter to search or preview, ↑/↓ to navigate, y to copy the URL, o to open in the browser, / to focus search field, and Ctrl-C exit."))
 	}
 	return text
 }
@@ -384,7 +659,7 @@ func (d searchItemDelegate) Render(w io.Writer, m list.Model, index int, item li
 
 	width := min(m.Width(), MaxWidth) - 1
 	lines := strings.Split(ansi.Hardwrap(sitem.description, width, false), "\n")
-	if len(lines) > 2 {
+	if len(lines) > 2 && len(lines[1]) > 5 {
 		lines = lines[:2]
 		lastLine := lines[1]
 		lines[1] = lastLine[:len(lastLine)-5] + "..."
@@ -505,3 +780,16 @@ func openBrowser(url string) error {
 	}
 	return nil
 }
+
+// copyToClipboard copies the text with the clipboard command of the OS.
+// Over SSH, where no clipboard command is available, it asks the terminal to copy the text with the OSC 52 escape sequence instead.
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		return errors.New("no clipboard is available")
+	}
+	termenv.NewOutput(os.Stdout).Copy(text)
+	return nil
+}
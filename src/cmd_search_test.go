@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+const testSearchResponse = `{"status": "ok", "records": [
+	{"id": "1", "title": "Guide", "contents": "Getting started", "url": "https://myproject.example.com/guide", "project_id": "p1", "project_slug": "myproject"},
+	{"id": "2", "title": "API\treference", "contents": "Search API", "url": "https://myproject.example.com/api/search", "project_id": "p1", "project_slug": "myproject"},
+	{"id": "3", "title": "Other", "contents": "Other project", "url": "https://other.example.com/", "project_id": "p2", "project_slug": "other"}
+]}`
+
+func newSearchTestServer(t *testing.T) (*httptest.Server, *openapi.SearchPostRequest) {
+	t.Helper()
+	searched := &openapi.SearchPostRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(searched) //nolint:errcheck
+		w.Write([]byte(testSearchResponse))      //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server, searched
+}
+
+func TestExecuteSearch(t *testing.T) {
+	t.Parallel()
+	env := EnvArgs{APIKey: "test-token"}
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+		server, searched := newSearchTestServer(t)
+		args := SearchArgs{query: []string{"search", "api"}, projects: []string{"p1", "p2"}, endpoint: server.URL, format: FormatPlain}
+		out := &bytes.Buffer{}
+		require.NoError(t, executeSearch(args, env, out))
+
+		assert.Equal(t, openapi.SearchPostRequest{Query: "search api", Projects: []string{"p1", "p2"}}, *searched)
+		expected := "myproject\tGuide\thttps://myproject.example.com/guide\n" +
+			"myproject\tAPI reference\thttps://myproject.example.com/api/search\n" +
+			"other\tOther\thttps://other.example.com/\n"
+		assert.Equal(t, expected, out.String())
+	})
+
+	t.Run("json with limit", func(t *testing.T) {
+		t.Parallel()
+		server, searched := newSearchTestServer(t)
+		args := SearchArgs{query: []string{"guide"}, limit: 1, endpoint: server.URL, format: FormatJSON}
+		out := &bytes.Buffer{}
+		require.NoError(t, executeSearch(args, env, out))
+
+		assert.Empty(t, searched.Projects)
+		output := openapi.SearchPostResponse{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &output))
+		require.Len(t, output.Records, 1)
+		assert.Equal(t, "Guide", output.Records[0].Title)
+	})
+}
+
+func TestCheckArgsAndEnvForSearch(t *testing.T) {
+	t.Parallel()
+	env := EnvArgs{APIKey: "test-token"}
+	tests := []struct {
+		name     string
+		args     SearchArgs
+		hasError bool
+	}{
+		{"tui", SearchArgs{endpoint: "https://example.com", format: FormatTUI}, false},
+		{"tui with query", SearchArgs{query: []string{"q"}, endpoint: "https://example.com", format: FormatTUI}, true},
+		{"plain", SearchArgs{query: []string{"q"}, endpoint: "https://example.com", format: FormatPlain}, false},
+		{"plain without query", SearchArgs{endpoint: "https://example.com", format: FormatPlain}, true},
+		{"negative limit", SearchArgs{query: []string{"q"}, limit: -1, endpoint: "https://example.com", format: FormatJSON}, true},
+		{"unknown format", SearchArgs{query: []string{"q"}, endpoint: "https://example.com", format: FormatText}, true},
+	}
+	for _, test := range tests {
+		err := CheckArgsAndEnvForSearch(test.args, env)
+		if test.hasError {
+			require.Error(t, err, test.name)
+		} else {
+			require.NoError(t, err, test.name)
+		}
+	}
+}
+
+func TestResolveSearchProjects(t *testing.T) {
+	prepareUploadProject(t)
+
+	assert.Equal(t, []string{"p1"}, resolveSearchProjects([]string{"p1"}, ".dodo.yaml"), "the flag should win over the config")
+	assert.Equal(t, []string{"project_id"}, resolveSearchProjects(nil, ".dodo.yaml"))
+	assert.Nil(t, resolveSearchProjects(nil, "missing.yaml"))
+
+	// The pages are not read, so a broken page does not affect the search.
+	require.NoError(t, os.WriteFile(".dodo.yaml", []byte("version: 2\nproject:\n  project_id: p2\npages:\n  - type: markdown\n    filepath: missing.md\n"), 0o600))
+	assert.Equal(t, []string{"p2"}, resolveSearchProjects(nil, ".dodo.yaml"))
+
+	require.NoError(t, os.WriteFile(".dodo.yaml", []byte("version: 2\n"), 0o600))
+	assert.Nil(t, resolveSearchProjects(nil, ".dodo.yaml"), "an invalid config should not scope the search")
+}
+
+func TestSearchPreview(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /search/v1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(testSearchResponse)) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /document/v1/myproject/guide", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status": "ok", "markdown": "# Guide\n\nInstall the CLI first."}`)) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	m, err := initialModel(SearchArgs{endpoint: server.URL, format: FormatTUI}, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+	m.markdownStyle = "notty"
+	update := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(model) //nolint:forcetypeassert
+		return cmd
+	}
+	update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	// Search, then open the first result in the preview pane.
+	m.textInput.SetValue("guide")
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Len(t, m.list.Items(), 3)
+	cmd := update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, m.previewOpen)
+	require.NotNil(t, cmd)
+	assert.Equal(t, 40, m.list.Width(), "the list should shrink to make room for the preview")
+	assert.Contains(t, m.View(), "Loading")
+
+	update(cmd())
+	assert.True(t, m.previewFocused)
+	assert.Contains(t, m.View(), "Install the CLI first.")
+
+	// A document which arrives after another item is selected is dropped.
+	update(previewMsg{url: "https://other.example.com/", markdown: "# Other"})
+	assert.NotContains(t, m.View(), "# Other")
+
+	// Find in the document, then go back to the list.
+	for _, k := range []string{"/", "c", "l", "i"} {
+		update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, m.preview.Matches(), 1)
+	assert.True(t, m.previewFocused, "Enter in the find prompt should not leave the preview")
+	update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.False(t, m.previewFocused)
+	assert.True(t, m.previewOpen)
+}
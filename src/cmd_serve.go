@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/log"
+	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/config"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkHTML "github.com/yuin/goldmark/renderer/html"
+)
+
+//go:embed templates/serve_page.html
+var servePageHTML string
+
+const (
+	serveDefaultAddr   = "127.0.0.1:8080"
+	serveBlobsPrefix   = "/" + BlobsDir + "/"
+	sidebarKindSection = "section"
+	sidebarKindDir     = "directory"
+	sidebarKindPage    = "page"
+)
+
+type ServeArgs struct {
+	configPath string // config file path
+	addr       string // address to listen on
+	debug      bool   // enable debug mode
+	noColor    bool   // disable color output
+}
+
+// Implement LoggingConfig and PrinterConfig interface for ServeArgs.
+func (opts *ServeArgs) DisableLogging() bool {
+	return false
+}
+
+func (opts *ServeArgs) EnableDebugMode() bool {
+	return opts.debug
+}
+
+func (opts *ServeArgs) EnableColor() bool {
+	return !opts.noColor
+}
+
+func (opts *ServeArgs) EnablePrinter() bool {
+	return true
+}
+
+func CreateServeCmd() *cobra.Command {
+	opts := ServeArgs{}
+	serveCmd := &cobra.Command{
+		Use:           "serve",
+		Short:         "Preview the project on a local server without uploading it",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			printer := NewErrorPrinter(ErrorLevel)
+			if err := InitLogger(&opts); err != nil {
+				return printer.HandleError(err)
+			}
+			if err := CheckArgsForServe(opts); err != nil {
+				return printer.HandleError(err)
+			}
+			printer = NewPrinterFromArgs(&opts)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			if err := serveCmdEntrypoint(ctx, opts); err != nil {
+				return printer.HandleError(err)
+			}
+			return nil
+		},
+	}
+	serveCmd.Flags().StringVarP(&opts.configPath, "config", "c", ".dodo.yaml", "Path to the configuration file")
+	serveCmd.Flags().StringVar(&opts.addr, "addr", serveDefaultAddr, "Address for the preview server to listen on")
+	serveCmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
+	serveCmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
+	return serveCmd
+}
+
+func CheckArgsForServe(args ServeArgs) error {
+	_, err := os.Stat(args.configPath)
+	if err != nil && os.IsNotExist(err) {
+		return fmt.Errorf("specified `config` argument is invalid. Please check if the file exists. Path: %s", args.configPath)
+	}
+	if err != nil {
+		return fmt.Errorf("specified `config` argument is invalid. Path: %s", args.configPath)
+	}
+	if args.addr == "" {
+		return errors.New("the `addr` argument must not be empty")
+	}
+	return nil
+}
+
+func serveCmdEntrypoint(ctx context.Context, args ServeArgs) error {
+	log.Debugf("config file: %s", args.configPath)
+	configFile, err := os.Open(args.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to open the config file: %w", err)
+	}
+	defer configFile.Close() //nolint:errcheck
+
+	metadata, err := parseConfigFileToMetadata(configFile, args.configPath)
+	if err != nil {
+		return err
+	}
+
+	handler, err := NewPreviewServer(metadata)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", args.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", args.addr, err)
+	}
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(listener)
+	}()
+	log.Infof("serving %d pages and %d assets", metadata.Page.Count(), len(metadata.Asset))
+	log.Infof("please open this link to view the document: http://%s/", listener.Addr().String())
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("the preview server stopped unexpectedly: %w", err)
+	case <-ctx.Done():
+		log.Infof("shutting down the preview server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil { //nolint:contextcheck
+			return fmt.Errorf("failed to shut down the preview server: %w", err)
+		}
+		return nil
+	}
+}
+
+// PreviewServer serves the pages and assets described by the Metadata as a local web site.
+// The markdown files are read on every request, so edits are visible after a reload.
+type PreviewServer struct {
+	metadata     *Metadata
+	pages        map[string]map[string]previewPage // language -> link -> page
+	firstLinks   map[string]string                 // language -> link of the first leaf page
+	assetsByHash map[string]MetadataAsset
+	assetsByPath map[string]MetadataAsset
+	markdown     goldmark.Markdown
+	template     *template.Template
+}
+
+type previewPage struct {
+	page *Page
+	info PageLanguageWiseInfo
+}
+
+type sidebarNode struct {
+	Kind     string
+	Title    string
+	URL      string
+	Active   bool
+	Children []sidebarNode
+}
+
+type languageLink struct {
+	Language string
+	URL      string
+	Active   bool
+}
+
+type servePageData struct {
+	ProjectName string
+	Title       string
+	Language    string
+	Languages   []languageLink
+	Sidebar     []sidebarNode
+	Body        template.HTML
+}
+
+func NewPreviewServer(metadata *Metadata) (*PreviewServer, error) {
+	tmpl, err := template.New("page").Parse(servePageHTML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the page template: %w", err)
+	}
+
+	s := &PreviewServer{
+		metadata:     metadata,
+		pages:        make(map[string]map[string]previewPage),
+		firstLinks:   make(map[string]string),
+		assetsByHash: make(map[string]MetadataAsset, len(metadata.Asset)),
+		assetsByPath: make(map[string]MetadataAsset, len(metadata.Asset)),
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			goldmark.WithRendererOptions(goldmarkHTML.WithUnsafe()),
+		),
+		template: tmpl,
+	}
+	s.indexPages(&metadata.Page)
+	for _, asset := range metadata.Asset {
+		s.assetsByHash[asset.Hash] = asset
+		s.assetsByPath[strings.TrimPrefix(asset.Path, "./")] = asset
+	}
+	return s, nil
+}
+
+func (s *PreviewServer) indexPages(p *Page) {
+	if p.Type == PageTypeLeafNode {
+		for _, info := range p.Language {
+			if _, ok := s.pages[info.Language]; !ok {
+				s.pages[info.Language] = make(map[string]previewPage)
+			}
+			s.pages[info.Language][info.Path] = previewPage{page: p, info: info}
+			if _, ok := s.firstLinks[info.Language]; !ok {
+				s.firstLinks[info.Language] = info.Path
+			}
+		}
+	}
+	for i := range p.Children {
+		s.indexPages(&p.Children[i])
+	}
+}
+
+func (s *PreviewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path := r.URL.Path
+	log.Debugf("%s %s", r.Method, path)
+
+	if path == "/" {
+		s.redirectToTop(w, r)
+		return
+	}
+	if strings.HasPrefix(path, serveBlobsPrefix) {
+		asset, ok := s.assetsByHash[strings.TrimPrefix(path, serveBlobsPrefix)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveAsset(w, r, asset)
+		return
+	}
+
+	lang, link, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if pages, ok := s.pages[lang]; ok {
+		if link == "" {
+			http.Redirect(w, r, "/"+lang+"/"+s.firstLinks[lang], http.StatusFound)
+			return
+		}
+		if entry, ok := pages[link]; ok {
+			s.servePage(w, r, lang, entry)
+			return
+		}
+		// Relative image links inside a page resolve under the language prefix.
+		if asset, ok := s.assetsByPath[link]; ok {
+			s.serveAsset(w, r, asset)
+			return
+		}
+	}
+	if asset, ok := s.assetsByPath[strings.TrimPrefix(path, "/")]; ok {
+		s.serveAsset(w, r, asset)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *PreviewServer) redirectToTop(w http.ResponseWriter, r *http.Request) {
+	lang := s.metadata.Project.DefaultLanguage
+	link, ok := s.firstLinks[lang]
+	if !ok {
+		http.Error(w, "the project has no pages for the default language", http.StatusNotFound)
+		return
+	}
+	http.Redirect(w, r, "/"+lang+"/"+link, http.StatusFound)
+}
+
+func (s *PreviewServer) servePage(w http.ResponseWriter, r *http.Request, lang string, entry previewPage) {
+	source, err := config.ReadMarkdownBody(entry.info.Filepath)
+	if err != nil {
+		log.Errorf("failed to read %s: %v", entry.info.Filepath, err)
+		http.Error(w, fmt.Sprintf("failed to read %s: %v", entry.info.Filepath, err), http.StatusInternalServerError)
+		return
+	}
+	body := &bytes.Buffer{}
+	if err := s.markdown.Convert(source, body); err != nil {
+		log.Errorf("failed to render %s: %v", entry.info.Filepath, err)
+		http.Error(w, fmt.Sprintf("failed to render %s: %v", entry.info.Filepath, err), http.StatusInternalServerError)
+		return
+	}
+
+	languages := make([]languageLink, 0, len(entry.page.Language))
+	for _, l := range entry.page.Language {
+		languages = append(languages, languageLink{
+			Language: l.Language,
+			URL:      "/" + l.Language + "/" + l.Path,
+			Active:   l.Language == lang,
+		})
+	}
+
+	data := servePageData{
+		ProjectName: s.metadata.Project.Name,
+		Title:       entry.info.Title,
+		Language:    lang,
+		Languages:   languages,
+		Sidebar:     s.buildSidebar(s.metadata.Page.Children, lang, entry.info.Path),
+		Body:        template.HTML(body.String()),
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.template.Execute(w, data); err != nil {
+		log.Errorf("failed to render the page template: %v", err)
+	}
+}
+
+func (s *PreviewServer) serveAsset(w http.ResponseWriter, r *http.Request, asset MetadataAsset) {
+	file, err := os.Open(asset.Path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to open %s: %v", asset.Path, err), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+
+	stat, err := file.Stat()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to stat %s: %v", asset.Path, err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", asset.EstimateMimeType())
+	http.ServeContent(w, r, asset.Path, stat.ModTime(), file)
+}
+
+// buildSidebar converts the page tree into sidebar entries for the given language.
+// Nodes without a translation fall back to the default language.
+func (s *PreviewServer) buildSidebar(pages []Page, lang, activeLink string) []sidebarNode {
+	nodes := make([]sidebarNode, 0, len(pages))
+	for i := range pages {
+		p := &pages[i]
+		info, ok := p.LanguageInfo(lang)
+		if !ok {
+			info, ok = p.LanguageInfo(s.metadata.Project.DefaultLanguage)
+		}
+		if !ok {
+			continue
+		}
+
+		node := sidebarNode{Title: info.Title}
+		switch p.Type {
+		case PageTypeSectionNode:
+			node.Kind = sidebarKindSection
+		case PageTypeLeafNode:
+			node.Kind = sidebarKindPage
+			node.URL = "/" + info.Language + "/" + info.Path
+			node.Active = info.Language == lang && info.Path == activeLink
+		default:
+			node.Kind = sidebarKindDir
+		}
+		node.Children = s.buildSidebar(p.Children, lang, activeLink)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
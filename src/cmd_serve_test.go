@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareServeMetadata(t *testing.T) *Metadata {
+	t.Helper()
+	dir := t.TempDir()
+	prepareFile(t, dir, "intro.en.md", "---\ntitle: Intro\nlink: intro\n---\n# Hello\n")
+	prepareFile(t, dir, "intro.ja.md", "---\ntitle: Intro JA\nlink: intro_ja\n---\n# Konnichiwa\n")
+	prepareFile(t, dir, "guide.md", "---\ntitle: Guide\nlink: guide\n---\n# Guide\n")
+	prepareFile(t, dir, "logo.png", "png")
+
+	return &Metadata{
+		Version: "1",
+		Project: MetadataProject{
+			ProjectID:       "project",
+			Name:            "Test Project",
+			DefaultLanguage: "en",
+		},
+		Page: Page{
+			Type: PageTypeRootNode,
+			Children: []Page{
+				{
+					Type: PageTypeLeafNode,
+					Language: []PageLanguageWiseInfo{
+						{Language: "en", Title: "Intro", Path: "intro", Filepath: filepath.Join(dir, "intro.en.md")},
+						{Language: "ja", Title: "Intro JA", Path: "intro_ja", Filepath: filepath.Join(dir, "intro.ja.md")},
+					},
+				},
+				{
+					Type:     PageTypeSectionNode,
+					Language: []PageLanguageWiseInfo{{Language: "en", Title: "Section"}},
+					Children: []Page{
+						{
+							Type: PageTypeLeafNode,
+							Language: []PageLanguageWiseInfo{
+								{Language: "en", Title: "Guide", Path: "guide", Filepath: filepath.Join(dir, "guide.md")},
+							},
+						},
+					},
+				},
+			},
+		},
+		Asset: []MetadataAsset{
+			{Path: filepath.Join(dir, "logo.png"), Hash: "logohash"},
+		},
+	}
+}
+
+func TestPreviewServer(t *testing.T) {
+	t.Parallel()
+	server, err := NewPreviewServer(prepareServeMetadata(t))
+	require.NoError(t, err)
+
+	t.Run("root redirects to the first page", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, "/en/intro", rec.Header().Get("Location"))
+	})
+
+	t.Run("page is rendered with the sidebar", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/en/intro", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.Contains(t, body, "<h1 id=\"hello\">Hello</h1>")
+		assert.NotContains(t, body, "link: intro", "front matter should not be rendered")
+		assert.Contains(t, body, "href=\"/en/guide\"")
+		assert.Contains(t, body, "Section")
+		assert.Contains(t, body, "href=\"/ja/intro_ja\"", "language switcher should be rendered")
+	})
+
+	t.Run("untranslated pages fall back to the default language", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ja/intro_ja", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.Contains(t, body, "Konnichiwa")
+		assert.Contains(t, body, "href=\"/en/guide\"")
+	})
+
+	t.Run("assets are served from blobs", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/blobs/logohash", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "png", rec.Body.String())
+	})
+
+	t.Run("unknown paths return 404", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/en/unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/caarlos0/log"
 	"github.com/spf13/cobra"
+	"github.com/toritoritori29/dodo-cli/src/api"
 	"github.com/toritoritori29/dodo-cli/src/config"
 )
 
@@ -18,14 +19,21 @@ var AvailableFormats = []string{ //nolint: gochecknoglobals
 }
 
 type UploadArgs struct {
-	file      string // config file path
-	output    string // deprecated: the path to locate the archive file
-	endpoint  string // server endpoint to upload
-	debug     bool   // server endpoint to upload
-	format    string // output style for the command
-	rootPath  string // root path of the project
-	noColor   bool   // disable color output
-	projectID string // override project_id from config
+	file        string // config file path
+	output      string // deprecated: the path to locate the archive file
+	endpoint    string // server endpoint to upload
+	debug       bool   // server endpoint to upload
+	format      string // output style for the command
+	rootPath    string // root path of the project
+	noColor     bool   // disable color output
+	projectID   string // override project_id from config
+	watch       bool   // re-run the command when files change
+	incremental bool   // negotiate a manifest and only send the blobs the server does not have
+	chunkSize   int64  // split the upload into chunks of this size in MiB. 0 sends a single request
+	resume      string // session ID of an interrupted chunked upload to resume
+	maxRetries  int    // number of retries for a failed request
+	archive     string // upload an archive built by `dodo pack` instead of the config file
+	dryRun      bool   // build the archive and report what would be sent without uploading it
 }
 
 // Implement LoggingConfig and PrinterConfig interface for UploadArgs.
@@ -59,10 +67,17 @@ func createUploadCommand(use, short, defaultEndpoint string, opts *UploadArgs, r
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug mode if set this flag")
 	cmd.Flags().StringVar(&opts.format, "format", "text", "Output format for the command. Supported formats: {text, json}")
 
-	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "archive file path") // Deprecated
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "archive file path")
+	cmd.Flags().MarkDeprecated("output", "use `dodo pack` to build an archive") //nolint:errcheck
 	cmd.Flags().StringVar(&opts.endpoint, "endpoint", defaultEndpoint, "endpoint to upload")
 	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable color output")
 	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Override the project_id from the config file")
+	cmd.Flags().BoolVar(&opts.incremental, "incremental", false, "Only send the files the server does not have yet")
+	cmd.Flags().Int64Var(&opts.chunkSize, "chunk-size", 0, "Upload the archive in chunks of this size in MiB. 0 sends it in a single request")
+	cmd.Flags().StringVar(&opts.resume, "resume", "", "Resume an interrupted chunked upload with the given session ID")
+	cmd.Flags().StringVar(&opts.archive, "archive", "", "Upload an archive built by `dodo pack` instead of reading the config file")
+	cmd.Flags().IntVar(&opts.maxRetries, "max-retries", api.DefaultMaxRetries, "Number of retries for a request that failed with a network error, 5xx or 429")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Validate the project and build the archive without uploading it")
 	return cmd
 }
 
@@ -102,6 +117,10 @@ func uploadCmdEntrypoint(args UploadArgs, env EnvArgs, jsonWriter *JSONWriter) e
 		return err
 	}
 
+	if args.dryRun {
+		return dryRunUpload(args, jsonWriter)
+	}
+
 	// Execute the upload operation.
 	url, err := executeUpload(args, env)
 	if err != nil {
@@ -114,43 +133,192 @@ func uploadCmdEntrypoint(args UploadArgs, env EnvArgs, jsonWriter *JSONWriter) e
 }
 
 func executeUpload(args UploadArgs, env EnvArgs) (string, error) {
-	// Read config file
-	log.Debugf("config file: %s", args.file)
-	configFile, err := os.Open(args.file)
+	client, err := newUploadClient(args, env)
 	if err != nil {
-		return "", fmt.Errorf("failed to open the config file: %w", err)
+		return "", err
+	}
+	if args.resume != "" {
+		uploader, err := newChunkedUploader(args, client)
+		if err != nil {
+			return "", err
+		}
+		resp, err := uploader.Resume(args.resume)
+		if err != nil {
+			return "", err
+		}
+		return resp.DocumentURL, nil
 	}
-	defer configFile.Close() //nolint:errcheck
 
-	metadata, err := parseConfigFileToMetadata(configFile, args)
+	archive, err := prepareUploadArchive(args, client)
 	if err != nil {
 		return "", err
 	}
+	defer archive.Close() //nolint:errcheck
 
-	// Override project_id if specified via flag
-	if args.projectID != "" {
-		metadata.Project.ProjectID = args.projectID
+	// Upload the archive file
+	if args.chunkSize > 0 {
+		uploader, err := newChunkedUploader(args, client)
+		if err != nil {
+			return "", err
+		}
+		resp, err := uploader.Upload(archive)
+		if err != nil {
+			return "", err
+		}
+		return resp.DocumentURL, nil
 	}
-
-	// Prepare archive file
-	archive, err := NewArchive(args.output)
+	resp, err := archive.Upload(client, args.endpoint, NewUploadProgressReporter(args))
 	if err != nil {
 		return "", err
 	}
+	return resp.DocumentURL, nil
+}
+
+// DryRunReport describes what an upload would send.
+type DryRunReport struct {
+	ProjectID string `json:"project_id"`
+	Endpoint  string `json:"endpoint"`
+	Pages     int    `json:"pages"`
+	Assets    int    `json:"assets"`
+	Files     int    `json:"files"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// dryRunUpload runs every step of the upload up to the archive creation and reports the result.
+// No request is sent to the server.
+func dryRunUpload(args UploadArgs, jsonWriter *JSONWriter) error {
+	report, err := executeDryRun(args)
+	if err != nil {
+		return err
+	}
+	log.Infof("dry run: nothing was uploaded")
+	log.Infof("project_id: %s", report.ProjectID)
+	log.Infof("endpoint: %s", report.Endpoint)
+	log.Infof("%d pages and %d assets. %d files (%s) would be sent", report.Pages, report.Assets, report.Files, formatBytes(report.Bytes))
+	jsonWriter.ShowDryRunJSONText(report)
+	return nil
+}
+
+func executeDryRun(args UploadArgs) (*DryRunReport, error) {
+	// The client is only used by --incremental, which is not allowed in a dry run.
+	client, err := newUploadClient(args, EnvArgs{})
+	if err != nil {
+		return nil, err
+	}
+	archive, err := prepareUploadArchive(args, client)
+	if err != nil {
+		return nil, err
+	}
 	defer archive.Close() //nolint:errcheck
-	if merr := archive.Archive(metadata); merr != nil {
-		return "", merr
+
+	info, err := archive.File.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat the archive file: %w", err)
 	}
+	return &DryRunReport{
+		ProjectID: archive.Metadata.Project.ProjectID,
+		Endpoint:  args.endpoint,
+		Pages:     archive.Metadata.Page.Count(),
+		Assets:    len(archive.Metadata.Asset),
+		Files:     len(archive.Metadata.ListBlobs()),
+		Bytes:     info.Size(),
+	}, nil
+}
 
-	// Upload the archive file
-	resp, err := archive.Upload(args.endpoint, env.BearerToken())
+// prepareUploadArchive opens the archive given by `--archive`, or builds a new one from the config file.
+func prepareUploadArchive(args UploadArgs, client *api.Client) (*Archive, error) {
+	if args.archive != "" {
+		log.Debugf("archive file: %s", args.archive)
+		archive, err := OpenArchive(args.archive)
+		if err != nil {
+			return nil, err
+		}
+		if args.projectID != "" {
+			archive.Metadata.Project.ProjectID = args.projectID
+		}
+		return archive, nil
+	}
+
+	metadata, err := loadMetadataFromConfig(args.file, args.projectID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return resp.DocumentURL, nil
+	archive, err := NewArchive(args.output)
+	if err != nil {
+		return nil, err
+	}
+	if args.incremental {
+		err = archiveIncremental(archive, metadata, client, args.endpoint)
+	} else if merr := archive.Archive(metadata); merr != nil {
+		err = merr
+	}
+	if err != nil {
+		archive.Close() //nolint:errcheck
+		return nil, err
+	}
+	return archive, nil
 }
 
-func parseConfigFileToMetadata(configFile *os.File, args UploadArgs) (*Metadata, error) {
+// loadMetadataFromConfig parses the config file and overrides project_id if projectID is given.
+func loadMetadataFromConfig(configPath, projectID string) (*Metadata, error) {
+	log.Debugf("config file: %s", configPath)
+	configFile, err := os.Open(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the config file: %w", err)
+	}
+	defer configFile.Close() //nolint:errcheck
+
+	metadata, err := parseConfigFileToMetadata(configFile, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Override project_id if specified via flag
+	if projectID != "" {
+		metadata.Project.ProjectID = projectID
+	}
+	return metadata, nil
+}
+
+// newUploadClient creates the API client for the upload endpoint.
+func newUploadClient(args UploadArgs, env EnvArgs) (*api.Client, error) {
+	return NewAPIClient(args.endpoint, env, api.WithTimeout(UploadTimeout), api.WithRetry(api.NewRetryPolicy(args.maxRetries)))
+}
+
+func newChunkedUploader(args UploadArgs, client *api.Client) (*ChunkedUploader, error) {
+	store, err := DefaultUploadSessionStore()
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkedUploader{
+		Client:    client,
+		Endpoint:  args.endpoint,
+		ChunkSize: args.chunkSize * MiB,
+		Store:     store,
+		Reporter:  NewUploadProgressReporter(args),
+	}, nil
+}
+
+// archiveIncremental sends the manifest to the server first and archives only the blobs it is missing.
+func archiveIncremental(archive *Archive, metadata *Metadata, client *api.Client, endpoint string) error {
+	manifest, err := NewUploadManifest(metadata)
+	if err != nil {
+		return err
+	}
+	resp, err := sendUploadManifestRequest(client, ManifestURL(endpoint), manifest)
+	if err != nil {
+		return err
+	}
+	log.Infof("sending %d of %d files (%d bytes)", len(resp.Missing), len(manifest.Blobs), manifest.Size(resp.Missing))
+
+	archive.ManifestID = resp.ManifestID
+	if merr := archive.ArchiveBlobs(metadata, manifest.SelectBlobs(resp.Missing)); merr != nil {
+		return merr
+	}
+	return nil
+}
+
+func parseConfigFileToMetadata(configFile *os.File, configPath string) (*Metadata, error) {
 	// Detect config version and parse the config file
 	version, err := config.DetectConfigVersion(configFile)
 	if err != nil {
@@ -164,14 +332,14 @@ func parseConfigFileToMetadata(configFile *os.File, args UploadArgs) (*Metadata,
 
 	switch version {
 	case 1:
-		state := config.NewParseStateV1(args.file, "./")
+		state := config.NewParseStateV1(configPath, "./")
 		conf, err := config.ParseConfigV1(state, configFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse the config file: %w", err)
 		}
 		return NewMetadataFromConfigV1(conf)
 	case 2:
-		state := config.NewParseStateV2(args.file, "./")
+		state := config.NewParseStateV2(configPath, "./")
 		conf, err := config.ParseConfigV2(state, configFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse the config file: %w", err)
@@ -188,18 +356,25 @@ func CheckArgsAndEnv(args UploadArgs, env EnvArgs) error { //nolint: cyclop
 		return errors.New("debug mode is only supported with text format")
 	}
 
-	// Check if `file` is valid
-	_, err := os.Stat(args.file)
-	if err != nil && os.IsNotExist(err) {
-		return fmt.Errorf("specified `file` argument is invalid. Please check if the file exists. Path: %s", args.file)
-	}
-	if err != nil {
-		return fmt.Errorf("specified `file` argument is invalid. Path: %s", args.file)
+	// Check if `archive` is valid. The config file is not read when an archive is given.
+	if args.archive != "" {
+		if err := checkArchiveArgs(args); err != nil {
+			return err
+		}
+	} else if args.resume == "" {
+		// Check if `file` is valid
+		_, err := os.Stat(args.file)
+		if err != nil && os.IsNotExist(err) {
+			return fmt.Errorf("specified `file` argument is invalid. Please check if the file exists. Path: %s", args.file)
+		}
+		if err != nil {
+			return fmt.Errorf("specified `file` argument is invalid. Path: %s", args.file)
+		}
 	}
 
 	// Check if the output path is valid
 	parentDir := filepath.Dir(args.output)
-	_, err = os.Stat(parentDir)
+	_, err := os.Stat(parentDir)
 	if err != nil && os.IsNotExist(err) {
 		return fmt.Errorf("specified output path is invalid. Please check if the parent directory exists. Path: %s", args.output)
 	}
@@ -216,15 +391,49 @@ func CheckArgsAndEnv(args UploadArgs, env EnvArgs) error { //nolint: cyclop
 		return fmt.Errorf("the provided `root` argument is invalid. Path: %s", args.rootPath)
 	}
 
-	// Check if any auth credential is available
-	if !env.IsAuthenticated() {
-		return errors.New("not authenticated. Please run 'dodo login' or set the DODO_API_KEY environment variable")
+	// Check if any auth credential is available. A dry run does not talk to the server.
+	if !args.dryRun && !env.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if args.dryRun && args.incremental {
+		return errors.New("`--incremental` cannot be used with `--dry-run`")
+	}
+	if args.dryRun && args.resume != "" {
+		return errors.New("`--resume` cannot be used with `--dry-run`")
 	}
 
 	// Check if the format is valid
 	if !slices.Contains(AvailableFormats, args.format) {
 		return fmt.Errorf("invalid format. Supported formats: %v", AvailableFormats)
 	}
+
+	// Check if the upload options are valid
+	if args.chunkSize < 0 {
+		return fmt.Errorf("invalid chunk size: %d", args.chunkSize)
+	}
+	if args.maxRetries < 0 {
+		return fmt.Errorf("invalid number of retries: %d", args.maxRetries)
+	}
+	return nil
+}
+
+func checkArchiveArgs(args UploadArgs) error {
+	info, err := os.Stat(args.archive)
+	if err != nil && os.IsNotExist(err) {
+		return fmt.Errorf("specified `archive` argument is invalid. Please check if the file exists. Path: %s", args.archive)
+	}
+	if err != nil || info.IsDir() {
+		return fmt.Errorf("specified `archive` argument is invalid. Path: %s", args.archive)
+	}
+	if args.incremental {
+		return errors.New("`--incremental` cannot be used with `--archive`")
+	}
+	if args.watch {
+		return errors.New("`--watch` cannot be used with `--archive`")
+	}
+	if args.resume != "" {
+		return errors.New("`--resume` cannot be used with `--archive`")
+	}
 	return nil
 }
 
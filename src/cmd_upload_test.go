@@ -0,0 +1,163 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const uploadTestConfig = `version: 2
+project:
+  project_id: "project_id"
+  name: "Test Project"
+pages:
+  - type: markdown
+    lang:
+      en:
+        filepath: "page1.md"
+        title: "Page 1"
+        link: "page1"
+  - type: markdown
+    lang:
+      en:
+        filepath: "page2.md"
+        title: "Page 2"
+        link: "page2"
+`
+
+// prepareUploadProject creates a small project in a temporary directory and moves into it.
+// The config parser resolves paths from the working directory, so the test cannot run in parallel.
+func prepareUploadProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	prepareFile(t, dir, ".dodo.yaml", uploadTestConfig)
+	prepareFile(t, dir, "page1.md", "# Page 1")
+	prepareFile(t, dir, "page2.md", "# Page 2")
+
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(oldWd) }) //nolint:errcheck
+	return dir
+}
+
+func TestExecuteUploadIncremental(t *testing.T) {
+	dir := prepareUploadProject(t)
+	server := newFakeServer(t)
+	env := EnvArgs{APIKey: "test-token"}
+	args := UploadArgs{
+		file:        ".dodo.yaml",
+		endpoint:    server.UploadURL(),
+		format:      FormatText,
+		rootPath:    ".",
+		incremental: true,
+	}
+
+	// The first upload sends every blob.
+	url, err := executeUpload(args, env)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/docs/project_id", url)
+	assert.Len(t, server.Received(0), 2)
+
+	// Nothing has changed, so the second upload only sends the metadata.
+	_, err = executeUpload(args, env)
+	require.NoError(t, err)
+	assert.Empty(t, server.Received(1))
+
+	// Only the modified page is sent, under the hash of its new contents.
+	prepareFile(t, dir, "page2.md", "# Page 2 updated")
+	_, err = executeUpload(args, env)
+	require.NoError(t, err)
+	require.Len(t, server.Received(2), 1)
+	assert.NotContains(t, server.Received(0), server.Received(2)[0])
+}
+
+func TestExecuteUploadFull(t *testing.T) {
+	prepareUploadProject(t)
+	server := newFakeServer(t)
+	env := EnvArgs{APIKey: "test-token"}
+	args := UploadArgs{
+		file:     ".dodo.yaml",
+		endpoint: server.UploadURL(),
+		format:   FormatText,
+		rootPath: ".",
+	}
+
+	// Without --incremental every upload contains all the blobs.
+	for i := range 2 {
+		_, err := executeUpload(args, env)
+		require.NoError(t, err)
+		assert.Len(t, server.Received(i), 2)
+	}
+	assert.Equal(t, 2, server.Uploads())
+}
+
+func TestUploadDryRun(t *testing.T) {
+	prepareUploadProject(t)
+	server := newFakeServer(t)
+	args := UploadArgs{
+		file:     ".dodo.yaml",
+		endpoint: server.UploadURL(),
+		format:   FormatText,
+		rootPath: ".",
+		dryRun:   true,
+	}
+
+	// No credentials are needed, as nothing is sent to the server.
+	require.NoError(t, CheckArgsAndEnv(args, EnvArgs{}))
+	report, err := executeDryRun(args)
+	require.NoError(t, err)
+	assert.Equal(t, "project_id", report.ProjectID)
+	assert.Equal(t, server.UploadURL(), report.Endpoint)
+	assert.Equal(t, 2, report.Pages)
+	assert.Zero(t, report.Assets)
+	assert.Equal(t, 2, report.Files)
+	assert.Positive(t, report.Bytes)
+
+	require.NoError(t, uploadCmdEntrypoint(args, EnvArgs{}, NewJSONWriter(JSONLogLevelDisabled)))
+	assert.Zero(t, server.Uploads())
+	assert.Zero(t, server.Requests("POST "+fakeUploadPath))
+
+	// Config errors are still reported.
+	require.NoError(t, os.Remove("page2.md"))
+	_, err = executeDryRun(args)
+	require.Error(t, err)
+
+	incremental := args
+	incremental.incremental = true
+	require.Error(t, CheckArgsAndEnv(incremental, EnvArgs{}))
+}
+
+func TestNewUploadManifest(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, "page1.md", "# Page 1")
+	prepareFile(t, dir, "image.png", "png")
+
+	metadata := &Metadata{
+		Page: Page{
+			Type: PageTypeRootNode,
+			Children: []Page{
+				{
+					Type:     PageTypeLeafNode,
+					Language: []PageLanguageWiseInfo{{Language: "en", Hash: "hash1", Filepath: filepath.Join(dir, "page1.md")}},
+				},
+			},
+		},
+		Asset: []MetadataAsset{{Path: filepath.Join(dir, "image.png"), Hash: "assets/hash2"}},
+	}
+	manifest, err := NewUploadManifest(metadata)
+	require.NoError(t, err)
+	require.Len(t, manifest.Blobs, 2)
+	assert.Equal(t, "hash1", manifest.Blobs[0].Hash)
+	assert.Equal(t, int64(8), manifest.Blobs[0].Size)
+	assert.Equal(t, "hash2", manifest.Blobs[1].Hash)
+	assert.Equal(t, int64(11), manifest.Size([]string{"hash1", "hash2"}))
+
+	selected := manifest.SelectBlobs([]string{"hash2"})
+	require.Len(t, selected, 1)
+	assert.Equal(t, filepath.Join(dir, "image.png"), selected[0].Filepath)
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// commentRemapper moves the comments of a config to the paths of the rewritten config.
+// Comments are keyed by the YAML path of their node, so the paths of the moved nodes are recorded while rewriting.
+type commentRemapper struct {
+	paths  map[string]string   // path in the original config -> path in the rewritten config
+	folded map[string]struct{} // paths of the removed keys. Their comments are moved to the page
+}
+
+func newCommentRemapper() commentRemapper {
+	return commentRemapper{
+		paths:  make(map[string]string),
+		folded: make(map[string]struct{}),
+	}
+}
+
+// move records that the node at old is written at path.
+func (r commentRemapper) move(old, path string) {
+	r.paths[old] = path
+}
+
+// fold records that the key at old is removed. Its comments are moved above the page at path.
+func (r commentRemapper) fold(old, path string) {
+	r.paths[old] = path
+	r.folded[old] = struct{}{}
+}
+
+// lookup returns the path where the node at old is written, if it is recorded.
+func (r commentRemapper) lookup(old string) (string, bool) {
+	path, ok := r.paths[old]
+	return path, ok
+}
+
+// remap moves the comments to the paths returned by place. A comment is dropped if place returns false.
+// The comments at first are visited before the others, and the rest in the order of their paths,
+// so that the comments merged into one node keep the order of the file.
+func (r commentRemapper) remap(comments yaml.CommentMap, first string, place func(path string, c *yaml.Comment) (string, bool)) yaml.CommentMap {
+	paths := make([]string, 0, len(comments))
+	for path := range comments {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if (paths[i] == first) != (paths[j] == first) {
+			return paths[i] == first
+		}
+		return paths[i] < paths[j]
+	})
+
+	remapped := yaml.CommentMap{}
+	for _, path := range paths {
+		for _, c := range comments[path] {
+			newPath, ok := place(path, c)
+			if !ok {
+				continue
+			}
+			if _, ok := r.folded[path]; ok {
+				c = yaml.HeadComment(c.Texts...)
+			}
+			remapped[newPath] = mergeComment(remapped[newPath], c)
+		}
+	}
+	return remapped
+}
+
+// mergeComment adds the comment to the list. A node can have only one comment per position,
+// so the texts are appended to the existing comment of the same position.
+func mergeComment(list []*yaml.Comment, c *yaml.Comment) []*yaml.Comment {
+	for _, existing := range list {
+		if existing.Position == c.Position {
+			existing.Texts = append(existing.Texts, c.Texts...)
+			return list
+		}
+	}
+	return append(list, &yaml.Comment{Texts: append([]string{}, c.Texts...), Position: c.Position})
+}
+
+func commentTexts(list []*yaml.Comment) []string {
+	texts := make([]string, 0, len(list))
+	for _, c := range list {
+		for _, text := range c.Texts {
+			texts = append(texts, strings.TrimSpace(text))
+		}
+	}
+	return texts
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// The canonical order of the keys. Unknown keys follow the known ones in the order of the file.
+var (
+	formatRootKeyOrder    = []string{"version", "project", "default_language", "pages", "assets", "annotation"}
+	formatProjectKeyOrder = []string{"project_id", "name", "description", "version", "logo", "repository", "default_language"}
+	formatPageKeyOrderV1  = []string{
+		ConfigPageKeyMarkdown, ConfigPageMatchKeyMatch, ConfigPageDirectoryKeyDirectory,
+		ConfigPageKeyTitle, ConfigPageKeyPath, ConfigPageKeyDescription, ConfigPageKeyCreatedAt, ConfigPageKeyUpdatedAt,
+		ConfigPageMatchKeySortKey, ConfigPageMatchKeySortOrder, ConfigPageDirectoryKeyChildren,
+	}
+	formatPageKeyOrderV2 = []string{
+		ConfigPageV2KeyType, ConfigPageV2KeyFile, ConfigPageV2KeyFilepath, ConfigPageV2KeyPattern, ConfigPageV2KeyTitle, ConfigPageV2KeyLink, ConfigPageV2KeyDesc,
+		ConfigPageV2KeySortKey, ConfigPageV2KeySortOrd, ConfigPageV2KeyLang, ConfigPageV2KeyChildren,
+	}
+	formatLangKeyOrderV2 = []string{ConfigPageV2KeyFilepath, ConfigPageV2KeyTitle, ConfigPageV2KeyLink, ConfigPageV2KeyDesc}
+)
+
+// formatSingleLanguageKeysV2 lists the keys each type of page accepts without `lang`.
+// A `lang` with only the default language is collapsed only if its entry fits in these keys.
+var formatSingleLanguageKeysV2 = map[string][]string{
+	ConfigPageTypeMarkdownV2:  {ConfigPageV2KeyFilepath, ConfigPageV2KeyTitle, ConfigPageV2KeyLink, ConfigPageV2KeyDesc},
+	ConfigPageTypeDirectoryV2: {ConfigPageV2KeyTitle},
+	ConfigPageTypeSectionV2:   {ConfigPageV2KeyTitle, ConfigPageV2KeyDesc},
+}
+
+// formatState keeps the state of the formatting.
+type formatState struct {
+	version         int
+	defaultLanguage string
+	comments        commentRemapper // paths in the original config -> paths in the formatted config
+}
+
+// FormatConfig rewrites a config into the canonical form:
+//   - the keys are sorted in the canonical order,
+//   - the values of `type` are lowercased,
+//   - a `lang` which has only the default language is collapsed into the single-language form,
+//   - the strings are quoted only when needed, with double quotes, and the sequences are indented.
+//
+// Comments are kept. The config is not validated beyond its version, so that an incomplete config can be formatted.
+func FormatConfig(reader io.Reader) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, reader); err != nil {
+		return nil, fmt.Errorf("failed to read a document config: %w", err)
+	}
+	contents := buf.Bytes()
+
+	version, err := DetectConfigVersion(bytes.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported config version: %d", version)
+	}
+
+	comments := yaml.CommentMap{}
+	var root yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions(contents, &root, yaml.CommentToMap(comments), yaml.UseOrderedMap()); err != nil {
+		return nil, fmt.Errorf("failed to parse a document config: %w", err)
+	}
+
+	f := &formatState{
+		version:         version,
+		defaultLanguage: SystemDefaultLanguageV2,
+		comments:        newCommentRemapper(),
+	}
+	if project, ok := mapOf(root, "project"); ok {
+		if lang := stringOf(project, "default_language"); lang != "" {
+			f.defaultLanguage = strings.ToLower(lang)
+		}
+	}
+
+	formatted := make(yaml.MapSlice, 0, len(root))
+	for _, item := range root {
+		switch item.Key {
+		case "project":
+			if project, ok := item.Value.(yaml.MapSlice); ok {
+				item.Value = sortMapKeys(project, formatProjectKeyOrder)
+			}
+		case "pages":
+			item.Value = f.formatPages(sequenceOf(item.Value), "$.pages")
+		}
+		// `assets` and `annotation` are kept as is. The order of `annotation` is up to the user.
+		formatted = append(formatted, item)
+	}
+	formatted = sortMapKeys(formatted, formatRootKeyOrder)
+
+	out, err := yaml.MarshalWithOptions(formatted, yaml.WithComment(f.remapComments(comments, root, formatted)), yaml.IndentSequence(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write the config: %w", err)
+	}
+	return out, nil
+}
+
+func (f *formatState) formatPages(items []any, path string) []any {
+	pages := make([]any, 0, len(items))
+	for i, item := range items {
+		entry, ok := item.(yaml.MapSlice)
+		if !ok {
+			pages = append(pages, item)
+			continue
+		}
+		pagePath := fmt.Sprintf("%s[%d]", path, i)
+		if f.version == 1 {
+			pages = append(pages, f.formatPageV1(entry, pagePath))
+		} else {
+			pages = append(pages, f.formatPageV2(entry, pagePath))
+		}
+	}
+	return pages
+}
+
+func (f *formatState) formatPageV1(entry yaml.MapSlice, path string) yaml.MapSlice {
+	page := make(yaml.MapSlice, 0, len(entry))
+	for _, item := range entry {
+		if item.Key == ConfigPageDirectoryKeyChildren {
+			item.Value = f.formatPages(sequenceOf(item.Value), path+"."+ConfigPageDirectoryKeyChildren)
+		}
+		page = append(page, item)
+	}
+	return sortMapKeys(page, formatPageKeyOrderV1)
+}
+
+func (f *formatState) formatPageV2(entry yaml.MapSlice, path string) yaml.MapSlice {
+	pageType := strings.ToLower(stringOf(entry, ConfigPageV2KeyType))
+	page := make(yaml.MapSlice, 0, len(entry))
+	for _, item := range entry {
+		switch item.Key {
+		case ConfigPageV2KeyType:
+			if pageType != "" {
+				item.Value = pageType
+			}
+		case ConfigPageV2KeyLang:
+			if collapsed, ok := f.collapseLang(entry, pageType, path); ok {
+				page = append(page, collapsed...)
+				continue
+			}
+			if langs, ok := item.Value.(yaml.MapSlice); ok {
+				item.Value = sortLangEntries(langs)
+			}
+		case ConfigPageV2KeyChildren:
+			item.Value = f.formatPages(sequenceOf(item.Value), path+"."+ConfigPageV2KeyChildren)
+		}
+		page = append(page, item)
+	}
+	return sortMapKeys(page, formatPageKeyOrderV2)
+}
+
+// collapseLang returns the items of the single-language form if `lang` has only the default language.
+// The page is kept as is if the entry does not fit in the single-language form, or if the page mixes both forms.
+func (f *formatState) collapseLang(entry yaml.MapSlice, pageType, path string) (yaml.MapSlice, bool) {
+	allowed, ok := formatSingleLanguageKeysV2[pageType]
+	if !ok {
+		return nil, false
+	}
+	langs, ok := mapOf(entry, ConfigPageV2KeyLang)
+	if !ok || len(langs) != 1 || !strings.EqualFold(fmt.Sprint(langs[0].Key), f.defaultLanguage) {
+		return nil, false
+	}
+	lang, ok := langs[0].Value.(yaml.MapSlice)
+	if !ok {
+		return nil, false
+	}
+	for _, item := range lang {
+		if key, _ := item.Key.(string); !slices.Contains(allowed, key) {
+			return nil, false
+		}
+	}
+	for _, key := range allowed {
+		if hasMapKey(entry, key) {
+			return nil, false
+		}
+	}
+
+	langPath := path + "." + ConfigPageV2KeyLang
+	entryPath := fmt.Sprintf("%s.%s", langPath, langs[0].Key)
+	f.comments.fold(langPath, path)
+	f.comments.fold(entryPath, path)
+	for _, item := range lang {
+		f.comments.move(fmt.Sprintf("%s.%s", entryPath, item.Key), fmt.Sprintf("%s.%s", path, item.Key))
+	}
+	return lang, true
+}
+
+// remapComments moves the comments to the paths of the formatted config.
+// The comments above the first key are the header of the file, so they move to the new first key.
+func (f *formatState) remapComments(comments yaml.CommentMap, root, formatted yaml.MapSlice) yaml.CommentMap {
+	header, first := "", ""
+	if len(root) > 0 {
+		header, first = fmt.Sprintf("$.%s", root[0].Key), fmt.Sprintf("$.%s", formatted[0].Key)
+	}
+	return f.comments.remap(comments, header, func(path string, c *yaml.Comment) (string, bool) {
+		if path == header && c.Position == yaml.CommentHeadPosition {
+			return first, true
+		}
+		if mapped, ok := f.comments.lookup(path); ok {
+			return mapped, true
+		}
+		return path, true
+	})
+}
+
+// sortMapKeys sorts the items by the order of the keys. Unknown keys follow in their original order.
+func sortMapKeys(mapping yaml.MapSlice, order []string) yaml.MapSlice {
+	rank := func(key any) int {
+		for i, k := range order {
+			if key == k {
+				return i
+			}
+		}
+		return len(order)
+	}
+	sorted := append(yaml.MapSlice{}, mapping...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank(sorted[i].Key) < rank(sorted[j].Key)
+	})
+	return sorted
+}
+
+// sortLangEntries sorts the keys of each language, keeping the order of the languages.
+func sortLangEntries(langs yaml.MapSlice) yaml.MapSlice {
+	sorted := make(yaml.MapSlice, 0, len(langs))
+	for _, item := range langs {
+		if entry, ok := item.Value.(yaml.MapSlice); ok {
+			item.Value = sortMapKeys(entry, formatLangKeyOrderV2)
+		}
+		sorted = append(sorted, item)
+	}
+	return sorted
+}
+
+func mapOf(mapping yaml.MapSlice, key string) (yaml.MapSlice, bool) {
+	for _, item := range mapping {
+		if item.Key == key {
+			v, ok := item.Value.(yaml.MapSlice)
+			return v, ok
+		}
+	}
+	return nil, false
+}
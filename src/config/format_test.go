@@ -0,0 +1,100 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatConfig(t *testing.T) {
+	t.Parallel()
+	input := `# yaml-language-server: $schema=.dodo.schema.json
+pages:
+- filepath: 'README.md'
+  type: Markdown
+  title: "Read me"  # shown in the sidebar
+# The guide
+- type: directory
+  children:
+  - type: markdown
+    # Only English for now
+    lang:
+      en:
+        title: Install
+        filepath: "docs/install.md"
+  lang:
+    en:
+      title: Guide
+    ja:
+      title: ガイド
+version: 2
+project:
+  name: "Test Project"
+  project_id: 'project_id'
+  version: "1.0"
+`
+	expected := `# yaml-language-server: $schema=.dodo.schema.json
+version: 2
+project:
+  project_id: project_id
+  name: Test Project
+  version: "1.0"
+pages:
+  - type: markdown
+    filepath: README.md
+    title: Read me # shown in the sidebar
+  # The guide
+  - type: directory
+    lang:
+      en:
+        title: Guide
+      ja:
+        title: ガイド
+    children:
+      # Only English for now
+      - type: markdown
+        filepath: docs/install.md
+        title: Install
+`
+	formatted, err := FormatConfig(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(formatted))
+
+	again, err := FormatConfig(strings.NewReader(expected))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(again), "formatting should be idempotent")
+}
+
+func TestFormatConfigKeepLang(t *testing.T) {
+	t.Parallel()
+	header := "version: 2\nproject:\n  project_id: id\n  name: name\n  default_language: ja\npages:\n"
+	cases := map[string]string{
+		"not the default language":   "  - type: markdown\n    lang:\n      en:\n        filepath: README.md\n",
+		"directory with description": "  - type: directory\n    lang:\n      ja:\n        title: Guide\n        description: guide\n    children:\n      - type: markdown\n        filepath: README.md\n",
+		"conflicting key":            "  - type: markdown\n    title: README\n    lang:\n      ja:\n        filepath: README.md\n",
+	}
+	for name, pages := range cases {
+		formatted, err := FormatConfig(strings.NewReader(header + pages))
+		require.NoError(t, err, name)
+		assert.Equal(t, header+pages, string(formatted), name)
+	}
+}
+
+func TestFormatConfigV1(t *testing.T) {
+	t.Parallel()
+	input := "version: 1\nproject:\n  name: name\n  project_id: id\npages:\n  - title: Guide\n    markdown: \"guide.md\"\n    path: guide\n"
+	expected := "version: 1\nproject:\n  project_id: id\n  name: name\npages:\n  - markdown: guide.md\n    title: Guide\n    path: guide\n"
+	formatted, err := FormatConfig(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(formatted))
+}
+
+func TestFormatConfigInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := FormatConfig(strings.NewReader("version: 3\n"))
+	require.ErrorContains(t, err, "unsupported config version")
+	_, err = FormatConfig(strings.NewReader("version: 2\npages: [\n"))
+	require.Error(t, err)
+}
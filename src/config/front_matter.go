@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -77,7 +78,7 @@ func NewFrontMatter(title string, path string, now ...time.Time) *FrontMatter {
 	}
 }
 
-func NewFrontMatterFromMarkdown(filepath string) (*FrontMatter, error) { //nolint: cyclop
+func NewFrontMatterFromMarkdown(filepath string) (*FrontMatter, error) {
 	if _, err := os.Stat(filepath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("file does not exist: %s", filepath)
 	}
@@ -86,12 +87,13 @@ func NewFrontMatterFromMarkdown(filepath string) (*FrontMatter, error) { //nolin
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
+	return NewFrontMatterFromReader(file)
+}
 
-	formats := []*frontmatter.Format{
-		frontmatter.NewFormat(FrontMatterStart, FrontMatterEnd, yaml.Unmarshal),
-	}
+// NewFrontMatterFromReader parses the front matter of a markdown given as a reader, e.g. a file being edited.
+func NewFrontMatterFromReader(reader io.Reader) (*FrontMatter, error) { //nolint: cyclop
 	var kv map[string]string
-	_, err = frontmatter.Parse(file, &kv, formats...)
+	_, err := frontmatter.Parse(reader, &kv, frontMatterFormats()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse front matter: %w", err)
 	}
@@ -137,6 +139,27 @@ func NewFrontMatterFromMarkdown(filepath string) (*FrontMatter, error) { //nolin
 	return &matter, nil
 }
 
+// ReadMarkdownBody returns the contents of the specified markdown file without its front matter.
+func ReadMarkdownBody(filepath string) ([]byte, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	return ReadMarkdownBodyFrom(file)
+}
+
+// ReadMarkdownBodyFrom returns the contents of a markdown given as a reader without its front matter.
+// The contents are returned as is if the markdown has no front matter.
+func ReadMarkdownBodyFrom(reader io.Reader) ([]byte, error) {
+	var v map[string]interface{}
+	body, err := frontmatter.Parse(reader, &v, frontMatterFormats()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse front matter: %w", err)
+	}
+	return body, nil
+}
+
 // UpdateMarkdown updates the front matter of the specified markdown file.
 // It keeps the remaining contents of the file intact.
 func (f *FrontMatter) UpdateMarkdown(filepath string) error {
@@ -146,11 +169,8 @@ func (f *FrontMatter) UpdateMarkdown(filepath string) error {
 	}
 	defer file.Close()
 
-	formats := []*frontmatter.Format{
-		frontmatter.NewFormat(FrontMatterStart, FrontMatterEnd, yaml.Unmarshal),
-	}
 	var v map[string]interface{}
-	remaining, err := frontmatter.Parse(file, &v, formats...)
+	remaining, err := frontmatter.Parse(file, &v, frontMatterFormats()...)
 	if err != nil {
 		return fmt.Errorf("failed to parse front matter: %w", err)
 	}
@@ -169,6 +189,12 @@ func (f *FrontMatter) UpdateMarkdown(filepath string) error {
 	return nil
 }
 
+func frontMatterFormats() []*frontmatter.Format {
+	return []*frontmatter.Format{
+		frontmatter.NewFormat(FrontMatterStart, FrontMatterEnd, yaml.Unmarshal),
+	}
+}
+
 func (f *FrontMatter) String() string {
 	// Prepare sorted unknown tag keys
 	sortedKeys := make([]string, 0, len(f.UnknownTags))
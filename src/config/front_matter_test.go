@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -140,3 +141,15 @@ UnknownTag2: unknown2
 		t.Errorf("expected %s, got %s", expected, fm.String())
 	}
 }
+
+func TestReadMarkdownBodyFrom(t *testing.T) {
+	t.Parallel()
+	body, err := ReadMarkdownBodyFrom(strings.NewReader("---\ntitle: Title\nlink: link\n---\n# Heading\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Heading\n", string(body))
+
+	// A markdown without front matter is returned as is.
+	body, err = ReadMarkdownBodyFrom(strings.NewReader("# Heading\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Heading\n", string(body))
+}
@@ -0,0 +1,311 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// MigrationV1 is the result of converting a version 1 config to version 2.
+type MigrationV1 struct {
+	// Contents is the version 2 config.
+	Contents []byte
+	// Notes describes the changes which are not a plain rename of the keys, e.g. removed fields.
+	Notes []string
+}
+
+// migrationStateV1 keeps the state of the conversion.
+// Comments are keyed by the YAML path of the node they belong to, so the paths of the version 1 nodes
+// are recorded along with the paths of the nodes which replace them.
+type migrationStateV1 struct {
+	parseState      *ParseStateV1
+	defaultLanguage string
+	comments        commentRemapper // paths of the version 1 nodes -> paths of the version 2 nodes
+	notes           []string
+}
+
+// MigrateConfigV1ToV2 converts a version 1 config into a version 2 config.
+// The config is validated with ParseConfigV1 first, so the conversion can assume a well-formed config.
+//
+// The conversion is done on the YAML document rather than on ConfigV1,
+// because ConfigV1 expands `match` statements and loses the values read from the front matter.
+// Comments are kept as long as the key they belong to still exists in version 2.
+func MigrateConfigV1ToV2(state *ParseStateV1, reader io.Reader) (*MigrationV1, error) {
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, reader); err != nil {
+		return nil, fmt.Errorf("failed to read a document config: %w", err)
+	}
+	contents := buf.Bytes()
+
+	conf, err := ParseConfigV1(state, bytes.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	comments := yaml.CommentMap{}
+	var root yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions(contents, &root, yaml.CommentToMap(comments), yaml.UseOrderedMap()); err != nil {
+		return nil, fmt.Errorf("failed to parse a document config: %w", err)
+	}
+
+	m := &migrationStateV1{
+		parseState:      state,
+		defaultLanguage: conf.Project.DefaultLanguage,
+		comments:        newCommentRemapper(),
+	}
+	migrated := make(yaml.MapSlice, 0, len(root))
+	for _, item := range root {
+		switch item.Key {
+		case "version":
+			migrated = append(migrated, yaml.MapItem{Key: item.Key, Value: 2})
+		case "pages":
+			pages, err := m.migratePages(sequenceOf(item.Value), "$.pages", "$.pages")
+			if err != nil {
+				return nil, err
+			}
+			migrated = append(migrated, yaml.MapItem{Key: item.Key, Value: pages})
+		default:
+			// `project`, `assets` and `annotation` have the same schema in both versions.
+			migrated = append(migrated, item)
+		}
+	}
+
+	out, err := yaml.MarshalWithOptions(migrated, yaml.WithComment(m.remapComments(comments)), yaml.IndentSequence(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write the version 2 config: %w", err)
+	}
+	return &MigrationV1{Contents: out, Notes: m.notes}, nil
+}
+
+func (m *migrationStateV1) migratePages(items []any, oldPath, newPath string) ([]any, error) {
+	pages := make([]any, 0, len(items))
+	for i, item := range items {
+		entry, _ := item.(yaml.MapSlice)
+		old := fmt.Sprintf("%s[%d]", oldPath, i)
+		switch {
+		case hasMapKey(entry, ConfigPageKeyMarkdown):
+			pages = append(pages, m.migrateMarkdown(entry, old, fmt.Sprintf("%s[%d]", newPath, len(pages))))
+		case hasMapKey(entry, ConfigPageMatchKeyMatch):
+			expanded, err := m.migrateMatch(entry, old, newPath, len(pages))
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, expanded...)
+		case hasMapKey(entry, ConfigPageDirectoryKeyDirectory):
+			page, err := m.migrateDirectory(entry, old, fmt.Sprintf("%s[%d]", newPath, len(pages)))
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+		default:
+			return nil, fmt.Errorf("unknown page type at %s", old)
+		}
+	}
+	return pages, nil
+}
+
+// migrateMarkdown converts `markdown: README.md` to `type: markdown` and `filepath: README.md`.
+func (m *migrationStateV1) migrateMarkdown(entry yaml.MapSlice, old, path string) yaml.MapSlice {
+	m.comments.move(old, path)
+	markdown := stringOf(entry, ConfigPageKeyMarkdown)
+	page := yaml.MapSlice{{Key: ConfigPageV2KeyType, Value: ConfigPageTypeMarkdownV2}}
+	for _, item := range entry {
+		key, _ := item.Key.(string)
+		switch key {
+		case ConfigPageKeyMarkdown:
+			m.comments.move(old+"."+key, path+"."+ConfigPageV2KeyFilepath)
+			page = append(page, yaml.MapItem{Key: ConfigPageV2KeyFilepath, Value: item.Value})
+		case ConfigPageKeyPath:
+			m.comments.move(old+"."+key, path+"."+ConfigPageV2KeyLink)
+			page = append(page, yaml.MapItem{Key: ConfigPageV2KeyLink, Value: item.Value})
+		case ConfigPageKeyUpdatedAt, ConfigPageKeyCreatedAt:
+			m.comments.fold(old+"."+key, path)
+			m.note("removed `%s` of %s: version 2 does not support it", key, markdown)
+		default:
+			m.comments.move(old+"."+key, path+"."+key)
+			page = append(page, item)
+		}
+	}
+	return page
+}
+
+// migrateMatch converts `match: docs/*.md` to `type: match` and `pattern: docs/*.md`.
+// A `match` statement is expanded into markdown entries when version 2 would build different pages from it:
+// version 2 cannot sort by date, and it groups the files which have `lang` in the front matter into one page.
+func (m *migrationStateV1) migrateMatch(entry yaml.MapSlice, old, newPath string, offset int) ([]any, error) { //nolint: cyclop
+	pattern := stringOf(entry, ConfigPageMatchKeyMatch)
+	sortKey := strings.ToLower(stringOf(entry, ConfigPageMatchKeySortKey))
+	sortOrder := strings.ToLower(stringOf(entry, ConfigPageMatchKeySortOrder))
+
+	reason, pages, err := m.expandMatch(pattern, sortKey, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		path := fmt.Sprintf("%s[%d]", newPath, offset)
+		m.comments.move(old, path)
+		page := yaml.MapSlice{{Key: ConfigPageV2KeyType, Value: ConfigPageTypeMatchV2}}
+		for _, item := range entry {
+			key, _ := item.Key.(string)
+			if key == ConfigPageMatchKeyMatch {
+				m.comments.move(old+"."+key, path+"."+ConfigPageV2KeyPattern)
+				page = append(page, yaml.MapItem{Key: ConfigPageV2KeyPattern, Value: item.Value})
+				continue
+			}
+			m.comments.move(old+"."+key, path+"."+key)
+			page = append(page, item)
+		}
+		return []any{page}, nil
+	}
+
+	m.note("expanded `match: %s` into %d markdown pages: %s", pattern, len(pages), reason)
+	expanded := make([]any, 0, len(pages))
+	for i, p := range pages {
+		if i == 0 {
+			// The comments of the statement are moved to the first page.
+			first := fmt.Sprintf("%s[%d]", newPath, offset)
+			m.comments.move(old, first)
+			for _, item := range entry {
+				m.comments.fold(fmt.Sprintf("%s.%s", old, item.Key), first)
+			}
+		}
+		expanded = append(expanded, yaml.MapSlice{
+			{Key: ConfigPageV2KeyType, Value: ConfigPageTypeMarkdownV2},
+			{Key: ConfigPageV2KeyFilepath, Value: p.Markdown},
+		})
+	}
+	return expanded, nil
+}
+
+// expandMatch lists the pages of a `match` statement in the order of version 1.
+// It also returns the reason why the statement cannot be kept as is, or an empty string if it can.
+func (m *migrationStateV1) expandMatch(pattern, sortKey, sortOrder string) (string, []ConfigPageV1, error) {
+	clean, err := m.parseState.getAbsolutePath(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	rootPath, err := filepath.Abs(m.parseState.rootPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get the absolute path of the working directory: %w", err)
+	}
+	matches, err := glob(clean)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list files matching '%s': %w", pattern, err)
+	}
+
+	reason := ""
+	if sortKey == ConfigPageKeyUpdatedAt || sortKey == ConfigPageKeyCreatedAt {
+		reason = fmt.Sprintf("version 2 cannot sort pages by `%s`", sortKey)
+	}
+	groups := make(map[string]struct{}, len(matches))
+	pages := make([]ConfigPageV1, 0, len(matches))
+	for _, match := range matches {
+		matter, err := NewFrontMatterFromMarkdown(match)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s", err, match)
+		}
+		relPath, err := filepath.Rel(rootPath, match)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get relative path: %w", err)
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if lang := matter.Lang(); reason == "" && lang != "" && lang != m.defaultLanguage {
+			reason = fmt.Sprintf("%s has `lang: %s` in the front matter, which version 2 treats as a translation", relPath, lang)
+		}
+		if _, ok := groups[matter.LanguageGroupID]; reason == "" && ok {
+			reason = fmt.Sprintf("%s shares the language group `%s` with another page", relPath, matter.LanguageGroupID)
+		}
+		groups[matter.LanguageGroupID] = struct{}{}
+
+		pages = append(pages, ConfigPageV1{
+			Markdown:  relPath,
+			Title:     matter.Title,
+			UpdatedAt: matter.UpdatedAt,
+			CreatedAt: matter.CreatedAt,
+		})
+	}
+	if err := sortPageSlice(sortKey, sortOrder, pages); err != nil {
+		return "", nil, err
+	}
+	return reason, pages, nil
+}
+
+// migrateDirectory converts `directory: Guide` to `type: directory` and `title: Guide`.
+func (m *migrationStateV1) migrateDirectory(entry yaml.MapSlice, old, path string) (yaml.MapSlice, error) {
+	m.comments.move(old, path)
+	title := stringOf(entry, ConfigPageDirectoryKeyDirectory)
+	page := yaml.MapSlice{{Key: ConfigPageV2KeyType, Value: ConfigPageTypeDirectoryV2}}
+	for _, item := range entry {
+		key, _ := item.Key.(string)
+		switch key {
+		case ConfigPageDirectoryKeyDirectory:
+			m.comments.move(old+"."+key, path+"."+ConfigPageV2KeyTitle)
+			page = append(page, yaml.MapItem{Key: ConfigPageV2KeyTitle, Value: item.Value})
+		case ConfigPageDirectoryKeyChildren:
+			m.comments.move(old+"."+key, path+"."+ConfigPageV2KeyChildren)
+			children, err := m.migratePages(sequenceOf(item.Value), old+"."+key, path+"."+ConfigPageV2KeyChildren)
+			if err != nil {
+				return nil, err
+			}
+			if len(children) == 0 {
+				m.note("the directory `%s` has no pages: version 2 requires at least one", title)
+			}
+			page = append(page, yaml.MapItem{Key: ConfigPageV2KeyChildren, Value: children})
+		default:
+			m.comments.move(old+"."+key, path+"."+key)
+			page = append(page, item)
+		}
+	}
+	return page, nil
+}
+
+func (m *migrationStateV1) note(format string, args ...any) {
+	m.notes = append(m.notes, fmt.Sprintf(format, args...))
+}
+
+// remapComments moves the comments to the paths of the version 2 config.
+// The comments outside of `pages` are kept as is because those sections are not changed.
+func (m *migrationStateV1) remapComments(comments yaml.CommentMap) yaml.CommentMap {
+	return m.comments.remap(comments, "", func(path string, c *yaml.Comment) (string, bool) {
+		if path != "$.pages" && !strings.HasPrefix(path, "$.pages[") {
+			return path, true
+		}
+		if mapped, ok := m.comments.lookup(path); ok {
+			return mapped, true
+		}
+		if path == "$.pages" {
+			return path, true
+		}
+		m.note("dropped a comment which could not be placed: %s", strings.Join(commentTexts([]*yaml.Comment{c}), " "))
+		return "", false
+	})
+}
+
+func hasMapKey(mapping yaml.MapSlice, key string) bool {
+	for _, item := range mapping {
+		if item.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func stringOf(mapping yaml.MapSlice, key string) string {
+	for _, item := range mapping {
+		if item.Key == key {
+			v, _ := item.Value.(string)
+			return v
+		}
+	}
+	return ""
+}
+
+func sequenceOf(value any) []any {
+	items, _ := value.([]any)
+	return items
+}
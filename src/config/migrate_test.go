@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const TestCaseForMigration = `# The config of the test project
+version: 1
+project:
+  project_id: "project_id"
+  name: "Test Project" # shown in the header
+  default_language: "en"
+pages:
+  # The top page
+  - markdown: "README1.md"
+    path: "readme1"
+    title: "README1"
+    updated_at: "2021-01-01T00:00:00Z" # kept for the sitemap
+  - directory: "Guide"
+    children:
+      - match: "guide/*.md"
+        sort_key: "title"
+  # Newest first
+  - match: "news/*.md"
+    sort_key: "updated_at"
+    sort_order: "desc"
+assets:
+  - "assets/**"
+`
+
+func TestMigrateConfigV1ToV2(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	createTempFile(t, dir, "README1.md")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "guide"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "guide", "a.md"), []byte("---\ntitle: A\nlink: a\n---\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "news"), 0o755))
+	for _, news := range []string{"old", "new"} {
+		date := "2024-01-01T00:00:00Z"
+		if news == "new" {
+			date = "2025-01-01T00:00:00Z"
+		}
+		contents := "---\ntitle: " + news + "\nlink: " + news + "\nupdated_at: " + date + "\n---\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "news", news+".md"), []byte(contents), 0o600))
+	}
+
+	state := NewParseStateV1("config.yaml", dir)
+	migration, err := MigrateConfigV1ToV2(state, strings.NewReader(TestCaseForMigration))
+	require.NoError(t, err)
+
+	expected := `# The config of the test project
+version: 2
+project:
+  project_id: project_id
+  name: Test Project # shown in the header
+  default_language: en
+pages:
+  # The top page
+  # kept for the sitemap
+  - type: markdown
+    filepath: README1.md
+    link: readme1
+    title: README1
+  - type: directory
+    title: Guide
+    children:
+      - type: match
+        pattern: guide/*.md
+        sort_key: title
+  # Newest first
+  - type: markdown
+    filepath: news/new.md
+  - type: markdown
+    filepath: news/old.md
+assets:
+  - assets/**
+`
+	assert.Equal(t, expected, string(migration.Contents))
+	assert.Equal(t, []string{
+		"removed `updated_at` of README1.md: version 2 does not support it",
+		"expanded `match: news/*.md` into 2 markdown pages: version 2 cannot sort pages by `updated_at`",
+	}, migration.Notes)
+
+	// The result must be a valid version 2 config.
+	conf, err := ParseConfigV2(NewParseStateV2("config.yaml", dir), bytes.NewReader(migration.Contents))
+	require.NoError(t, err)
+	require.Len(t, conf.Pages, 4)
+	assert.Equal(t, "new", conf.Pages[2].LangPage["en"].Title)
+}
+
+func TestMigrateConfigV1ToV2ExpandsTranslations(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.md"), []byte("---\ntitle: A\nlink: a\n---\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.md"), []byte("---\ntitle: B\nlink: b\nlang: ja\n---\n"), 0o600))
+
+	config := "version: 1\nproject:\n  project_id: id\n  name: name\npages:\n  - match: \"*.md\" # all pages\n"
+	migration, err := MigrateConfigV1ToV2(NewParseStateV1("config.yaml", dir), strings.NewReader(config))
+	require.NoError(t, err)
+
+	expected := "version: 2\nproject:\n  project_id: id\n  name: name\npages:\n" +
+		"  # all pages\n  - type: markdown\n    filepath: a.md\n  - type: markdown\n    filepath: b.md\n"
+	assert.Equal(t, expected, string(migration.Contents))
+	require.Len(t, migration.Notes, 1)
+	assert.Contains(t, migration.Notes[0], "b.md has `lang: ja`")
+}
+
+func TestMigrateConfigV1ToV2InvalidConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	_, err := MigrateConfigV1ToV2(NewParseStateV1("config.yaml", dir), strings.NewReader("version: 1\npages: []\n"))
+	require.Error(t, err, "the config should be validated before the migration")
+}
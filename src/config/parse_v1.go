@@ -16,7 +16,6 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/ast"
 	"github.com/goccy/go-yaml/parser"
-	"github.com/mattn/go-zglob"
 	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
 	"golang.org/x/text/language"
 )
@@ -53,6 +52,9 @@ type ConfigV1 struct {
 	Project ConfigProjectV1
 	Pages   []ConfigPageV1
 	Assets  []ConfigAssetV1
+
+	// MatchPatterns holds the absolute glob patterns of `match` entries expanded while parsing.
+	MatchPatterns []string
 }
 
 type ConfigProjectV1 struct {
@@ -117,7 +119,7 @@ func (m ConfigAssetV1) List(rootDir string) ([]string, error) {
 		return nil, fmt.Errorf("invalid configuration: path must be under the root directory: path: %s", globPath)
 	}
 
-	matches, err := zglob.Glob(globPath)
+	matches, err := glob(globPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list files matching '%s': %w", globPath, err)
 	}
@@ -725,7 +727,8 @@ func buildConfigPageFromMatchStatement(state *ParseStateV1, mapping *ast.Mapping
 		return nil
 	}
 
-	matches, err := zglob.Glob(clean)
+	state.config.MatchPatterns = append(state.config.MatchPatterns, clean)
+	matches, err := glob(clean)
 	if err != nil {
 		state.errorSet.Add(state.buildParseError(fmt.Sprintf("failed to list files matching '%s': %v", match, err), mapping))
 		return nil
@@ -800,19 +803,19 @@ func sortPageSlice(sortKey, sortOrder string, pages []ConfigPageV1) error { //no
 	}
 
 	if sortKey == "title" {
-		sort.Slice(pages, func(i, j int) bool {
+		sort.SliceStable(pages, func(i, j int) bool {
 			return (pages[i].Title < pages[j].Title) == isASC
 		})
 		return nil
 	}
 	if sortKey == "updated_at" {
-		sort.Slice(pages, func(i, j int) bool {
+		sort.SliceStable(pages, func(i, j int) bool {
 			return (pages[i].UpdatedAt.Before(pages[j].UpdatedAt.Time)) == isASC
 		})
 		return nil
 	}
 	if sortKey == "created_at" {
-		sort.Slice(pages, func(i, j int) bool {
+		sort.SliceStable(pages, func(i, j int) bool {
 			return (pages[i].CreatedAt.Before(pages[j].CreatedAt.Time)) == isASC
 		})
 		return nil
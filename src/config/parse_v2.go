@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,7 +16,6 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/ast"
 	"github.com/goccy/go-yaml/parser"
-	"github.com/mattn/go-zglob"
 	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
 	"github.com/toritoritori29/dodo-cli/src/utils"
 )
@@ -27,6 +28,7 @@ const (
 	ConfigPageTypeDirectoryMultiLanguageV2 = "directory_multilanguage"
 	ConfigPageTypeSectionV2                = "section"
 	ConfigPageTypeSectionV2MultiLanguage   = "section_multilanguage"
+	ConfigPageTypeIncludeV2                = "include"
 )
 
 const (
@@ -40,6 +42,7 @@ const (
 	ConfigPageV2KeySortKey  = "sort_key"
 	ConfigPageV2KeySortOrd  = "sort_order"
 	ConfigPageV2KeyChildren = "children"
+	ConfigPageV2KeyFile     = "file"
 )
 
 const (
@@ -51,6 +54,13 @@ type ConfigV2 struct {
 	Project ConfigProjectV2
 	Pages   []ConfigPageV2
 	Assets  []ConfigAssetV2
+
+	// MatchPatterns holds the absolute glob patterns of `type: match` entries.
+	// They are expanded into pages while parsing, so they are kept here for consumers that need to re-expand them.
+	MatchPatterns []string
+
+	// IncludedFiles holds the absolute paths of the files loaded by `type: include` entries.
+	IncludedFiles []string
 }
 
 type ConfigProjectV2 struct {
@@ -120,7 +130,7 @@ func (m ConfigAssetV2) List(rootDir string) ([]string, error) {
 		return nil, fmt.Errorf("invalid configuration: path must be under the root directory: path: %s", globPath)
 	}
 
-	matches, err := zglob.Glob(globPath)
+	matches, err := glob(globPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list files matching '%s': %w", globPath, err)
 	}
@@ -138,6 +148,11 @@ type ParseStateV2 struct {
 	isAssetsAlreadyParsed     bool
 	isAnnotationAlreadyParsed bool
 	errorSet                  appErrors.MultiError
+
+	// baseDir is the absolute directory of the included file being parsed. Empty while parsing the config itself.
+	baseDir string
+	// includes holds the absolute paths of the files being parsed, from the config itself, to detect include cycles.
+	includes []string
 }
 
 func NewParseStateV2(filepath, workingDir string) *ParseStateV2 {
@@ -187,6 +202,23 @@ func (s *ParseStateV2) getAbsolutePath(path string) (string, error) {
 	return absPath, nil
 }
 
+// getRootRelativePath converts a path written in the file being parsed into a path relative to the root directory.
+// The paths in an included file are relative to the directory of that file.
+func (s *ParseStateV2) getRootRelativePath(path string) string {
+	if s.baseDir == "" {
+		return path
+	}
+	absRootPath, err := filepath.Abs(s.rootPath)
+	if err != nil {
+		return path
+	}
+	relPath, err := filepath.Rel(absRootPath, filepath.Join(s.baseDir, path))
+	if err != nil {
+		return path
+	}
+	return relPath
+}
+
 // getRelativePath converts an absolute path to a relative path and validates it's under the root directory.
 func (s *ParseStateV2) getRelativePath(absPath string) (string, error) {
 	absRootPath, err := filepath.Abs(s.rootPath)
@@ -454,6 +486,9 @@ func parseConfigPageSequenceV2(state *ParseStateV2, sequence *ast.SequenceNode)
 		case ConfigPageTypeSectionV2MultiLanguage:
 			p := parseConfigPageSectionMultiLanguageV2(state, pageNode)
 			configPages = append(configPages, p)
+		case ConfigPageTypeIncludeV2:
+			pages := parseConfigPageIncludeV2(state, pageNode)
+			configPages = append(configPages, pages...)
 		default:
 			state.errorSet.Add(state.buildParseError("unknown page type", pageNode))
 		}
@@ -494,7 +529,7 @@ func estimateConfigPageTypeV2(state *ParseStateV2, mapping *ast.MappingNode) str
 				return ConfigPageTypeSectionV2MultiLanguage
 			}
 			return ConfigPageTypeSectionV2
-		case ConfigPageTypeMatchV2:
+		case ConfigPageTypeMatchV2, ConfigPageTypeIncludeV2:
 			return strings.ToLower(v.Value)
 		default:
 			state.errorSet.Add(state.buildParseError("unknown page type: "+v.Value, item.Value))
@@ -545,7 +580,7 @@ func parseConfigPageMarkdownV2(state *ParseStateV2, mapping *ast.MappingNode) Co
 				state.errorSet.Add(state.buildParseError("`filepath` field must be a string", item.Value))
 				continue
 			}
-			langItem.Filepath = v.Value
+			langItem.Filepath = state.getRootRelativePath(v.Value)
 		default:
 			state.errorSet.Add(state.buildParseError("a markdown style page cannot accept the key: "+key, item))
 		}
@@ -631,7 +666,7 @@ func parseMarkdownLangEntriesV2(state *ParseStateV2, langNode *ast.MappingNode)
 					state.errorSet.Add(state.buildParseError("`filepath` field must be a string", child.Value))
 					continue
 				}
-				langConfig.Filepath = v.Value
+				langConfig.Filepath = state.getRootRelativePath(v.Value)
 			default:
 				state.errorSet.Add(state.buildParseError("a markdown language entry cannot accept the key: "+childKey, child))
 			}
@@ -713,7 +748,7 @@ func validateLangKeySetV2(state *ParseStateV2, mapping *ast.MappingNode, keySet
 		return
 	}
 	if _, ok := keySet[defaultLang]; !ok {
-		supported := strings.Join(utils.Keys(keySet), ",")
+		supported := strings.Join(utils.SortedKeys(keySet), ",")
 		message := fmt.Sprintf("the default language (%s) is not included in the `lang` keys. existing languages: [%s]", defaultLang, supported)
 		state.errorSet.Add(state.buildParseError(message, mapping))
 	}
@@ -780,19 +815,22 @@ func parseConfigPageMatchV2(state *ParseStateV2, mapping *ast.MappingNode) []Con
 }
 
 func buildConfigPageFromMatchStatementV2(state *ParseStateV2, mapping *ast.MappingNode, pattern, sortKey, sortOrder string) []ConfigPageV2 {
-	clean, err := state.getAbsolutePath(pattern)
+	clean, err := state.getAbsolutePath(state.getRootRelativePath(pattern))
 	if err != nil {
 		state.errorSet.Add(state.buildParseError(err.Error(), mapping))
 		return nil
 	}
-	matches, err := zglob.Glob(clean)
+	state.config.MatchPatterns = append(state.config.MatchPatterns, clean)
+	matches, err := glob(clean)
 	if err != nil {
 		message := fmt.Sprintf("failed to list files matching '%s': %v", pattern, err)
 		state.errorSet.Add(state.buildParseError(message, mapping))
 		return nil
 	}
 
+	// Pages keep the order in which their group first appears in the matches.
 	pagesByGroupID := make(map[string]ConfigPageV2)
+	groupIDs := make([]string, 0, len(matches))
 	for _, m := range matches {
 		matter, err := NewFrontMatterFromMarkdown(m)
 		if err != nil {
@@ -826,6 +864,7 @@ func buildConfigPageFromMatchStatementV2(state *ParseStateV2, mapping *ast.Mappi
 		page, ok := pagesByGroupID[matter.LanguageGroupID]
 		// If not exists, create a new page entry.
 		if !ok {
+			groupIDs = append(groupIDs, matter.LanguageGroupID)
 			pagesByGroupID[matter.LanguageGroupID] = ConfigPageV2{
 				Type: ConfigPageTypeMarkdownMultiLanguageV2,
 				LangPage: map[string]ConfigPageLangPage{
@@ -844,7 +883,10 @@ func buildConfigPageFromMatchStatementV2(state *ParseStateV2, mapping *ast.Mappi
 		pagesByGroupID[matter.LanguageGroupID] = page
 	}
 
-	pages := utils.Values(pagesByGroupID)
+	pages := make([]ConfigPageV2, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		pages = append(pages, pagesByGroupID[groupID])
+	}
 	if err := sortPageSliceV2(sortKey, sortOrder, pages, state.config.Project.DefaultLanguage); err != nil {
 		state.errorSet.Add(state.buildParseError(err.Error(), mapping))
 		return nil
@@ -881,7 +923,7 @@ func sortPageSliceV2(sortKey, sortOrder string, pages []ConfigPageV2, defaultLan
 	}
 
 	if sortKey == "title" {
-		sort.Slice(pages, func(i, j int) bool {
+		sort.SliceStable(pages, func(i, j int) bool {
 			left := pages[i].SortKeyTitle(defaultLang)
 			right := pages[j].SortKeyTitle(defaultLang)
 			return (left < right) == isASC
@@ -1152,6 +1194,96 @@ func validateConfigPageSection(state *ParseStateV2, page ConfigPageV2, mapping *
 	}
 }
 
+// Parse Include Page ---------------------------------------------------------
+
+// parseConfigPageIncludeV2 parses the pages of another file in place of the entry.
+// The file holds a sequence of pages, and the relative paths in it are resolved against its own directory.
+func parseConfigPageIncludeV2(state *ParseStateV2, mapping *ast.MappingNode) []ConfigPageV2 {
+	var file string
+	for _, item := range mapping.Values {
+		key := item.Key.String()
+		switch key {
+		case ConfigPageV2KeyType:
+			continue
+		case ConfigPageV2KeyFile:
+			v, ok := item.Value.(*ast.StringNode)
+			if !ok {
+				state.errorSet.Add(state.buildParseError("`file` field must be a string", item.Value))
+				continue
+			}
+			file = v.Value
+		default:
+			state.errorSet.Add(state.buildParseError("an include style page cannot accept the key: "+key, item))
+		}
+	}
+	if file == "" {
+		state.errorSet.Add(state.buildParseError("the `file` field is required", mapping))
+		return nil
+	}
+
+	relPath := state.getRootRelativePath(file)
+	clean, err := state.getAbsolutePath(relPath)
+	if err != nil {
+		state.errorSet.Add(state.buildParseError(err.Error(), mapping))
+		return nil
+	}
+	if len(state.includes) == 0 {
+		if absPath, err := filepath.Abs(state.filepath); err == nil {
+			state.includes = append(state.includes, absPath)
+		}
+	}
+	for i, included := range state.includes {
+		if included != clean {
+			continue
+		}
+		cycle := make([]string, 0, len(state.includes)-i+1)
+		for _, p := range append(slices.Clone(state.includes[i:]), clean) {
+			if rel, err := state.getRelativePath(p); err == nil {
+				p = rel
+			}
+			cycle = append(cycle, p)
+		}
+		message := "include cycle detected: " + strings.Join(cycle, " -> ")
+		state.errorSet.Add(state.buildParseError(message, mapping))
+		return nil
+	}
+
+	contents, err := os.ReadFile(clean)
+	if err != nil {
+		message := fmt.Sprintf("cannot read the included file: %s, %v", file, err)
+		state.errorSet.Add(state.buildParseError(message, mapping))
+		return nil
+	}
+	root, err := parser.ParseBytes(contents, parser.Mode(0))
+	if err != nil {
+		message := fmt.Sprintf("failed to parse the included file: %s, %v", file, err)
+		state.errorSet.Add(state.buildParseError(message, mapping))
+		return nil
+	}
+	var sequence *ast.SequenceNode
+	if len(root.Docs) == 1 {
+		sequence, _ = root.Docs[0].Body.(*ast.SequenceNode)
+	}
+	if sequence == nil {
+		message := fmt.Sprintf("the included file must be a sequence of pages: %s", file)
+		state.errorSet.Add(state.buildParseError(message, mapping))
+		return nil
+	}
+	state.config.IncludedFiles = append(state.config.IncludedFiles, clean)
+
+	// Parse the pages as a part of the included file, so that the errors point to the file and its lines.
+	outerFilepath, outerContents, outerBaseDir := state.filepath, state.contents, state.baseDir
+	state.filepath = filepath.Join(state.rootPath, relPath)
+	state.contents = contents
+	state.baseDir = filepath.Dir(clean)
+	state.includes = append(state.includes, clean)
+	defer func() {
+		state.filepath, state.contents, state.baseDir = outerFilepath, outerContents, outerBaseDir
+		state.includes = state.includes[:len(state.includes)-1]
+	}()
+	return parseConfigPageSequenceV2(state, sequence)
+}
+
 // Other Sections --------------------------------------------------------------
 func parseConfigAssetsV2(state *ParseStateV2, node *ast.MappingValueNode) {
 	if state.isAssetsAlreadyParsed {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
 )
 
 func loadTestCaseV2(t *testing.T, name string) (string, string) {
@@ -157,6 +158,37 @@ func TestParseConfigV2(t *testing.T) {
 				assert.Equal(t, "child2_ja", matchChild2.LangPage["ja"].Link)
 			},
 		},
+		{
+			name:     "include",
+			caseName: "3_valid_include",
+			assert: func(t *testing.T, conf *ConfigV2) {
+				require.Len(t, conf.Pages, 4, "the included pages should replace the include entry")
+				assert.Equal(t, "README.md", conf.Pages[0].LangPage["en"].Filepath)
+
+				// The paths in an included file are relative to the file.
+				page := conf.Pages[1]
+				assert.Equal(t, ConfigPageTypeMarkdownV2, page.Type)
+				assert.Equal(t, filepath.Join("guide", "intro.md"), page.LangPage["en"].Filepath)
+				assert.Equal(t, "Introduction", page.LangPage["en"].Title)
+
+				page = conf.Pages[2]
+				assert.Equal(t, ConfigPageTypeDirectoryV2, page.Type)
+				require.Len(t, page.Children, 2)
+				assert.Equal(t, filepath.Join("guide", "tutorials", "a.md"), page.Children[0].LangPage["en"].Filepath)
+				assert.Equal(t, filepath.Join("guide", "tutorials", "b.md"), page.Children[1].LangPage["en"].Filepath)
+
+				// Includes nested in children and in other included files.
+				page = conf.Pages[3]
+				assert.Equal(t, ConfigPageTypeSectionV2, page.Type)
+				require.Len(t, page.Children, 2)
+				assert.Equal(t, filepath.Join("api", "reference.md"), page.Children[0].LangPage["en"].Filepath)
+				assert.Equal(t, filepath.Join("api", "v2", "changes.md"), page.Children[1].LangPage["en"].Filepath)
+				assert.Equal(t, "Changes in v2", page.Children[1].LangPage["en"].Title)
+				assert.Equal(t, "v2_changes", page.Children[1].LangPage["en"].Link)
+
+				assert.Len(t, conf.IncludedFiles, 3)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -171,3 +203,56 @@ func TestParseConfigV2(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConfigV2IncludeErrors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile := func(name, contents string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+	writeFile("team/pages.yaml", "- type: markdown\n  filepath: a.md\n  path: a\n")
+	writeFile("team/a.md", "---\ntitle: A\nlink: a\n---\n")
+	writeFile("cycle/a.yaml", "- type: include\n  file: b.yaml\n")
+	writeFile("cycle/b.yaml", "- type: include\n  file: a.yaml\n")
+	writeFile("mapping.yaml", "pages: []\n")
+
+	parse := func(pages string) *appErrors.MultiError {
+		t.Helper()
+		input := "version: 2\nproject:\n  project_id: id\n  name: name\npages:\n" + pages
+		_, err := ParseConfigV2(NewParseStateV2(filepath.Join(dir, ".dodo.yaml"), dir), strings.NewReader(input))
+		require.Error(t, err)
+		var merr *appErrors.MultiError
+		require.ErrorAs(t, err, &merr)
+		return merr
+	}
+
+	// An error in an included file points to the file and its line.
+	merr := parse("  - type: include\n    file: team/pages.yaml\n")
+	require.Equal(t, 1, merr.Length())
+	var parseErr *appErrors.ParseError
+	require.ErrorAs(t, merr.Errors()[0], &parseErr)
+	assert.Equal(t, filepath.Join(dir, "team", "pages.yaml"), parseErr.Filepath)
+	assert.Equal(t, 3, parseErr.Node.GetToken().Position.Line)
+	assert.Equal(t, "  path: a", parseErr.Line)
+
+	merr = parse("  - type: include\n    file: cycle/a.yaml\n")
+	require.Equal(t, 1, merr.Length())
+	require.ErrorAs(t, merr.Errors()[0], &parseErr)
+	assert.Contains(t, parseErr.Message, "include cycle detected")
+	assert.Contains(t, parseErr.Message, filepath.Join("cycle", "a.yaml")+" -> "+filepath.Join("cycle", "b.yaml")+" -> "+filepath.Join("cycle", "a.yaml"))
+	assert.Equal(t, filepath.Join(dir, "cycle", "b.yaml"), parseErr.Filepath)
+
+	invalid := map[string]string{
+		"missing file":       "  - type: include\n    file: missing.yaml\n",
+		"without file":       "  - type: include\n",
+		"unknown key":        "  - type: include\n    file: team/pages.yaml\n    title: Team\n",
+		"not a sequence":     "  - type: include\n    file: mapping.yaml\n",
+		"outside of root":    "  - type: include\n    file: ../pages.yaml\n",
+		"include the config": "  - type: include\n    file: .dodo.yaml\n",
+	}
+	for name, pages := range invalid {
+		assert.Positive(t, parse(pages).Length(), name)
+	}
+}
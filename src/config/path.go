@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/mattn/go-zglob"
 )
 
 func IsUnderRootPath(root string, path string) error {
@@ -30,3 +33,14 @@ func cleanAbs(path string) (string, error) {
 	}
 	return absPath, nil
 }
+
+// glob lists the files matching the pattern in lexical order.
+// zglob walks directories concurrently, so its result order differs between runs.
+func glob(pattern string) ([]string, error) {
+	matches, err := zglob.Glob(pattern)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
@@ -0,0 +1,300 @@
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+	JSONSchemaTitle = "dodo-doc configuration (.dodo.yaml)"
+)
+
+// NewJSONSchema returns the JSON Schema of the config for the given version.
+// Version 0 returns a schema which accepts both versions and selects one by the `version` field.
+//
+// The schema is written with the same keys as the parsers, so that editors can complete and validate `.dodo.yaml`.
+// It only checks the structure. The existence of the files and the values read from the front matter are checked by `dodo check`.
+func NewJSONSchema(version int) (map[string]any, error) {
+	schema := map[string]any{
+		"$schema":     JSONSchemaDraft,
+		"title":       JSONSchemaTitle,
+		"definitions": schemaDefinitions(),
+	}
+	switch version {
+	case 0:
+		schema["if"] = map[string]any{
+			"properties": map[string]any{"version": map[string]any{"const": 1}},
+			"required":   []string{"version"},
+		}
+		schema["then"] = schemaRef("configV1")
+		schema["else"] = schemaRef("configV2")
+	case 1:
+		// `$ref` cannot have siblings in draft-07.
+		schema["allOf"] = []any{schemaRef("configV1")}
+	case 2:
+		schema["allOf"] = []any{schemaRef("configV2")}
+	default:
+		return nil, fmt.Errorf("unsupported config version: %d", version)
+	}
+	return schema, nil
+}
+
+func schemaDefinitions() map[string]any {
+	return map[string]any{
+		"project":    schemaProject(),
+		"assets":     schemaArray(schemaString("A glob pattern of the images, relative to the config file"), "The images used in the pages"),
+		"annotation": map[string]any{"description": "Free-form data. It is not used by dodo"},
+
+		// Version 1
+		"configV1":        schemaConfig(1, "pageV1"),
+		"pageV1":          schemaPageV1(),
+		"pageMarkdownV1":  schemaPageMarkdownV1(),
+		"pageMatchV1":     schemaPageMatch(1),
+		"pageDirectoryV1": schemaPageDirectoryV1(),
+
+		// Version 2
+		"configV2":       schemaConfig(2, "pageV2"),
+		"pageV2":         schemaPageV2(),
+		"pageMarkdownV2": schemaPageMarkdownV2(),
+		"pageMatchV2":    schemaPageMatch(2),
+		"pageDirectoryV2": schemaObject(map[string]any{
+			ConfigPageV2KeyType:     schemaPageTypeV2(),
+			ConfigPageV2KeyTitle:    schemaString("The title of the directory"),
+			ConfigPageV2KeyChildren: schemaChildrenV2(1),
+		}, ConfigPageV2KeyTitle, ConfigPageV2KeyChildren),
+		"pageSectionV2": schemaObject(map[string]any{
+			ConfigPageV2KeyType:     schemaPageTypeV2(),
+			ConfigPageV2KeyTitle:    schemaString("The title of the section"),
+			ConfigPageV2KeyDesc:     schemaString("The description of the section"),
+			ConfigPageV2KeyChildren: schemaChildrenV2(0),
+		}, ConfigPageV2KeyTitle),
+		"pageIncludeV2": schemaObject(map[string]any{
+			ConfigPageV2KeyType: schemaPageTypeV2(),
+			ConfigPageV2KeyFile: schemaString("The path of a YAML file which lists the pages. Its paths are relative to the file"),
+		}, ConfigPageV2KeyFile),
+		"pageMarkdownMultiLanguageV2":  schemaPageMultiLanguageV2("langPageV2", nil),
+		"pageDirectoryMultiLanguageV2": schemaPageMultiLanguageV2("langTitleV2", schemaChildrenV2(1), ConfigPageV2KeyChildren),
+		"pageSectionMultiLanguageV2":   schemaPageMultiLanguageV2("langTitleV2", schemaChildrenV2(0)),
+		"languageCodeV2":               schemaLanguageCode(),
+		"langPageV2":                   schemaLangPageV2(),
+		"langTitleV2":                  schemaLangTitleV2(),
+	}
+}
+
+func schemaConfig(version int, page string) map[string]any {
+	return schemaObject(map[string]any{
+		"version":    map[string]any{"type": "integer", "const": version, "description": "The version of the config format"},
+		"project":    schemaRef("project"),
+		"pages":      schemaArray(schemaRef(page), "The pages of the project, in the order of the navigation"),
+		"assets":     schemaRef("assets"),
+		"annotation": schemaRef("annotation"),
+	}, "version", "project", "pages")
+}
+
+func schemaProject() map[string]any {
+	repository := schemaString("The URL of the repository of the project")
+	repository["format"] = "uri"
+	defaultLanguage := schemaString("The ISO 639-1 code of the default language. Defaults to `en`")
+	defaultLanguage["pattern"] = "^[A-Za-z]{2}$"
+	return schemaObject(map[string]any{
+		"project_id":       schemaString("The ID of the project"),
+		"name":             schemaString("The name of the project"),
+		"description":      schemaString("The description of the project"),
+		"version":          schemaString("The version of the documented product"),
+		"logo":             schemaString("The path of the logo image"),
+		"repository":       repository,
+		"default_language": defaultLanguage,
+	}, "project_id", "name")
+}
+
+// Version 1 ------------------------------------------------------------------
+
+// schemaPageV1 selects the kind of a page by its keys, as estimateConfigPageType does.
+func schemaPageV1() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"if":   map[string]any{"required": []string{ConfigPageKeyMarkdown}},
+		"then": schemaRef("pageMarkdownV1"),
+		"else": map[string]any{
+			"if":   map[string]any{"required": []string{ConfigPageMatchKeyMatch}},
+			"then": schemaRef("pageMatchV1"),
+			"else": schemaRef("pageDirectoryV1"),
+		},
+	}
+}
+
+func schemaPageMarkdownV1() map[string]any {
+	path := schemaString("The URL path of the page. Defaults to `link` in the front matter")
+	path["pattern"] = "^[a-zA-Z0-9_-]+$"
+	updatedAt := schemaString("The last update time in RFC3339")
+	updatedAt["format"] = "date-time"
+	createdAt := schemaString("The creation time in RFC3339")
+	createdAt["format"] = "date-time"
+	return schemaObject(map[string]any{
+		ConfigPageKeyMarkdown:    schemaString("The path of the markdown file"),
+		ConfigPageKeyTitle:       schemaString("The title of the page. Defaults to `title` in the front matter"),
+		ConfigPageKeyPath:        path,
+		ConfigPageKeyDescription: schemaString("The description of the page"),
+		ConfigPageKeyUpdatedAt:   updatedAt,
+		ConfigPageKeyCreatedAt:   createdAt,
+	}, ConfigPageKeyMarkdown)
+}
+
+func schemaPageDirectoryV1() map[string]any {
+	return schemaObject(map[string]any{
+		ConfigPageDirectoryKeyDirectory: schemaString("The title of the directory"),
+		ConfigPageDirectoryKeyChildren:  schemaArray(schemaRef("pageV1"), "The pages in the directory"),
+	}, ConfigPageDirectoryKeyDirectory)
+}
+
+// schemaPageMatch returns the schema of `match` pages.
+// Version 2 renames `match` to `pattern` and can sort the pages only by the title.
+func schemaPageMatch(version int) map[string]any {
+	sortKey := schemaString("The key to sort the matched pages by")
+	sortOrder := schemaString("The order of the pages. Requires `sort_key`")
+	sortOrder["enum"] = []string{"asc", "desc"}
+	properties := map[string]any{
+		ConfigPageMatchKeySortKey:   sortKey,
+		ConfigPageMatchKeySortOrder: sortOrder,
+	}
+
+	patternKey := ConfigPageMatchKeyMatch
+	sortKey["enum"] = []string{"title", ConfigPageKeyUpdatedAt, ConfigPageKeyCreatedAt}
+	if version == 2 {
+		patternKey = ConfigPageV2KeyPattern
+		sortKey["enum"] = []string{"title"}
+		properties[ConfigPageV2KeyType] = schemaPageTypeV2()
+	}
+	properties[patternKey] = schemaString("A glob pattern of the markdown files")
+
+	schema := schemaObject(properties, patternKey)
+	schema["dependencies"] = map[string]any{ConfigPageMatchKeySortOrder: []string{ConfigPageMatchKeySortKey}}
+	return schema
+}
+
+// Version 2 ------------------------------------------------------------------
+
+// schemaPageV2 selects the kind of a page by `type` and the presence of `lang`, as estimateConfigPageTypeV2 does.
+func schemaPageV2() map[string]any {
+	pageType := schemaString("The page type")
+	pageType["enum"] = []string{ConfigPageTypeMarkdownV2, ConfigPageTypeMatchV2, ConfigPageTypeDirectoryV2, ConfigPageTypeSectionV2, ConfigPageTypeIncludeV2}
+	return map[string]any{
+		"type":       "object",
+		"required":   []string{ConfigPageV2KeyType},
+		"properties": map[string]any{ConfigPageV2KeyType: pageType},
+		"allOf": []any{
+			schemaIfType(ConfigPageTypeMarkdownV2, schemaIfLang("pageMarkdownMultiLanguageV2", "pageMarkdownV2")),
+			schemaIfType(ConfigPageTypeMatchV2, schemaRef("pageMatchV2")),
+			schemaIfType(ConfigPageTypeDirectoryV2, schemaIfLang("pageDirectoryMultiLanguageV2", "pageDirectoryV2")),
+			schemaIfType(ConfigPageTypeSectionV2, schemaIfLang("pageSectionMultiLanguageV2", "pageSectionV2")),
+			schemaIfType(ConfigPageTypeIncludeV2, schemaRef("pageIncludeV2")),
+		},
+	}
+}
+
+func schemaPageMarkdownV2() map[string]any {
+	return schemaObject(map[string]any{
+		ConfigPageV2KeyType:     schemaPageTypeV2(),
+		ConfigPageV2KeyFilepath: schemaString("The path of the markdown file"),
+		ConfigPageV2KeyTitle:    schemaString("The title of the page. Defaults to `title` in the front matter"),
+		ConfigPageV2KeyLink:     schemaString("The URL path of the page. Defaults to `link` in the front matter"),
+		ConfigPageV2KeyDesc:     schemaString("The description of the page"),
+	}, ConfigPageV2KeyFilepath)
+}
+
+// schemaPageMultiLanguageV2 returns the schema of a page with `lang`, which maps the language codes to the entries of the page.
+func schemaPageMultiLanguageV2(entry string, children map[string]any, required ...string) map[string]any {
+	properties := map[string]any{
+		ConfigPageV2KeyType: schemaPageTypeV2(),
+		ConfigPageV2KeyLang: map[string]any{
+			"type":                 "object",
+			"description":          "The page in each language, keyed by the ISO 639-1 code. The default language is required",
+			"minProperties":        1,
+			"propertyNames":        schemaRef("languageCodeV2"),
+			"additionalProperties": schemaRef(entry),
+		},
+	}
+	if children != nil {
+		properties[ConfigPageV2KeyChildren] = children
+	}
+	return schemaObject(properties, append([]string{ConfigPageV2KeyLang}, required...)...)
+}
+
+func schemaLanguageCode() map[string]any {
+	code := schemaString("An ISO 639-1 language code")
+	code["pattern"] = "^[a-z]{2}$"
+	return code
+}
+
+func schemaLangPageV2() map[string]any {
+	return schemaObject(map[string]any{
+		ConfigPageV2KeyFilepath: schemaString("The path of the markdown file"),
+		ConfigPageV2KeyTitle:    schemaString("The title of the page. Defaults to `title` in the front matter"),
+		ConfigPageV2KeyLink:     schemaString("The URL path of the page. Defaults to `link` in the front matter"),
+		ConfigPageV2KeyDesc:     schemaString("The description of the page"),
+	}, ConfigPageV2KeyFilepath)
+}
+
+func schemaLangTitleV2() map[string]any {
+	return schemaObject(map[string]any{
+		ConfigPageV2KeyTitle: schemaString("The title"),
+		ConfigPageV2KeyDesc:  schemaString("The description"),
+	}, ConfigPageV2KeyTitle)
+}
+
+// schemaPageTypeV2 allows `type` in a page. The value is checked by pageV2, which selects the schema of the page by it.
+func schemaPageTypeV2() map[string]any {
+	return schemaString("The page type")
+}
+
+func schemaChildrenV2(minItems int) map[string]any {
+	children := schemaArray(schemaRef("pageV2"), "The pages in this page")
+	if minItems > 0 {
+		children["minItems"] = minItems
+	}
+	return children
+}
+
+func schemaIfType(pageType string, then map[string]any) map[string]any {
+	return map[string]any{
+		"if": map[string]any{
+			"properties": map[string]any{ConfigPageV2KeyType: map[string]any{"const": pageType}},
+			"required":   []string{ConfigPageV2KeyType},
+		},
+		"then": then,
+	}
+}
+
+func schemaIfLang(multiLanguage, singleLanguage string) map[string]any {
+	return map[string]any{
+		"if":   map[string]any{"required": []string{ConfigPageV2KeyLang}},
+		"then": schemaRef(multiLanguage),
+		"else": schemaRef(singleLanguage),
+	}
+}
+
+// Helpers --------------------------------------------------------------------
+
+func schemaObject(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func schemaArray(items map[string]any, description string) map[string]any {
+	return map[string]any{"type": "array", "items": items, "description": description}
+}
+
+func schemaString(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/definitions/" + name}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileJSONSchema(t *testing.T, version int) *jsonschema.Schema {
+	t.Helper()
+	schema, err := NewJSONSchema(version)
+	require.NoError(t, err)
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("dodo.schema.json", doc))
+	compiled, err := compiler.Compile("dodo.schema.json")
+	require.NoError(t, err)
+	return compiled
+}
+
+func validateWithJSONSchema(t *testing.T, schema *jsonschema.Schema, config string) error {
+	t.Helper()
+	data, err := yaml.YAMLToJSON([]byte(config))
+	require.NoError(t, err)
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+	return schema.Validate(instance) //nolint:wrapcheck
+}
+
+// TestJSONSchemaV2 keeps the schema in sync with ParseConfigV2.
+// Every fixture used by TestParseConfigV2 must be accepted by both, and the invalid configs must be rejected by both.
+func TestJSONSchemaV2(t *testing.T) {
+	t.Parallel()
+	schema := compileJSONSchema(t, 0)
+
+	entries, err := os.ReadDir(filepath.Join("test_cases", "v2"))
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		dir, input := loadTestCaseV2(t, entry.Name())
+		_, err := ParseConfigV2(NewParseStateV2("config.yaml", dir), strings.NewReader(input))
+		require.NoError(t, err, entry.Name())
+		require.NoError(t, validateWithJSONSchema(t, schema, input), entry.Name())
+	}
+
+	dir, _ := loadTestCaseV2(t, "1_valid_single_language")
+	header := "version: 2\nproject:\n  project_id: id\n  name: name\n"
+	invalid := map[string]string{
+		"version as a string":         "version: \"2\"\nproject:\n  project_id: id\n  name: name\npages: []\n",
+		"missing project name":        "version: 2\nproject:\n  project_id: id\npages: []\n",
+		"unknown top-level key":       header + "pages: []\nextra: 1\n",
+		"unknown page type":           header + "pages:\n  - type: page\n    filepath: README.md\n",
+		"missing page type":           header + "pages:\n  - filepath: README.md\n",
+		"unknown markdown key":        header + "pages:\n  - type: markdown\n    filepath: README.md\n    path: readme\n",
+		"single-locale key with lang": header + "pages:\n  - type: markdown\n    title: README\n    lang:\n      en:\n        filepath: README.md\n",
+		"invalid language code":       header + "pages:\n  - type: markdown\n    lang:\n      english:\n        filepath: README.md\n",
+		"sort_order without sort_key": header + "pages:\n  - type: match\n    pattern: \"*.md\"\n    sort_order: asc\n",
+		"sort by updated_at":          header + "pages:\n  - type: match\n    pattern: \"*.md\"\n    sort_key: updated_at\n",
+		"directory without children":  header + "pages:\n  - type: directory\n    title: Docs\n    children: []\n",
+		"directory with description":  header + "pages:\n  - type: directory\n    title: Docs\n    description: docs\n    children:\n      - type: markdown\n        filepath: README.md\n",
+		"section without title":       header + "pages:\n  - type: section\n    children: []\n",
+		"include without file":        header + "pages:\n  - type: include\n",
+		"include with title":          header + "pages:\n  - type: include\n    file: pages.yaml\n    title: Docs\n",
+	}
+	for name, input := range invalid {
+		_, err := ParseConfigV2(NewParseStateV2("config.yaml", dir), strings.NewReader(input))
+		require.Error(t, err, "the parser should reject: %s", name)
+		require.Error(t, validateWithJSONSchema(t, schema, input), "the schema should reject: %s", name)
+	}
+}
+
+func TestJSONSchemaV1(t *testing.T) {
+	t.Parallel()
+	schema := compileJSONSchema(t, 1)
+	require.NoError(t, validateWithJSONSchema(t, schema, TestCaseForDetailCheckMarkdown))
+	require.NoError(t, validateWithJSONSchema(t, schema, TestCaseForMigration))
+
+	header := "version: 1\nproject:\n  project_id: id\n  name: name\n"
+	require.Error(t, validateWithJSONSchema(t, schema, header+"pages:\n  - markdown: README.md\n    link: readme\n"))
+	require.Error(t, validateWithJSONSchema(t, schema, header+"pages:\n  - title: README\n"))
+	require.Error(t, validateWithJSONSchema(t, schema, "version: 2\nproject:\n  project_id: id\n  name: name\npages: []\n"),
+		"the schema of version 1 should reject version 2")
+}
+
+func TestNewJSONSchemaUnknownVersion(t *testing.T) {
+	t.Parallel()
+	_, err := NewJSONSchema(3)
+	assert.Error(t, err)
+}
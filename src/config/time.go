@@ -44,6 +44,19 @@ func (t SerializableTime) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.Format(time.RFC3339))
 }
 
+func (t *SerializableTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to unmarshal time: %w", err)
+	}
+	parsed, err := NewSerializableTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
 func (t *SerializableTime) HasValue() bool {
 	return !t.IsZero()
 }
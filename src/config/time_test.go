@@ -47,3 +47,26 @@ func TestSerializableTimeMarshalJSON(t *testing.T) {
 		t.Fatalf("json.Marshal(nonZero) = %s, expected RFC3339 string", nonZeroBytes)
 	}
 }
+
+func TestSerializableTimeUnmarshalJSON(t *testing.T) {
+	var zero SerializableTime
+	if err := json.Unmarshal([]byte(`""`), &zero); err != nil {
+		t.Fatalf("json.Unmarshal(\"\") error = %v", err)
+	}
+	if zero.HasValue() {
+		t.Fatalf("json.Unmarshal(\"\") = %v, expected zero time", zero)
+	}
+
+	var nonZero SerializableTime
+	if err := json.Unmarshal([]byte(`"2025-01-01T00:00:00Z"`), &nonZero); err != nil {
+		t.Fatalf("json.Unmarshal(nonZero) error = %v", err)
+	}
+	if !nonZero.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("json.Unmarshal(nonZero) = %v, expected 2025-01-01T00:00:00Z", nonZero)
+	}
+
+	var invalid SerializableTime
+	if err := json.Unmarshal([]byte(`"yesterday"`), &invalid); err == nil {
+		t.Fatalf("json.Unmarshal(invalid) should return an error")
+	}
+}
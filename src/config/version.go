@@ -12,22 +12,9 @@ import (
 // DetectConfigVersion parses the YAML and returns the top-level version number.
 // The reader is fully consumed by this function.
 func DetectConfigVersion(reader io.Reader) (int, error) {
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, reader); err != nil {
-		return 0, fmt.Errorf("failed to read config: %w", err)
-	}
-
-	root, err := parser.ParseBytes(buf.Bytes(), parser.Mode(0))
+	body, err := parseRootMapping(reader)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse config: %w", err)
-	}
-	if len(root.Docs) != 1 {
-		return 0, fmt.Errorf("there should be only one document. Got %d", len(root.Docs))
-	}
-
-	body, ok := root.Docs[0].Body.(*ast.MappingNode)
-	if !ok {
-		return 0, fmt.Errorf("the root node must be of mapping type")
+		return 0, err
 	}
 
 	for _, mapping := range body.Values {
@@ -50,3 +37,54 @@ func DetectConfigVersion(reader io.Reader) (int, error) {
 
 	return 0, fmt.Errorf("the `version` field is required")
 }
+
+// DetectProjectID returns `project.project_id` of the config without parsing the pages.
+// The `project` section is the same in both versions. The reader is fully consumed by this function.
+func DetectProjectID(reader io.Reader) (string, error) {
+	body, err := parseRootMapping(reader)
+	if err != nil {
+		return "", err
+	}
+
+	for _, mapping := range body.Values {
+		if mapping.Key.String() != "project" {
+			continue
+		}
+		project, ok := mapping.Value.(*ast.MappingNode)
+		if !ok {
+			return "", fmt.Errorf("`project` must be a mapping")
+		}
+		for _, item := range project.Values {
+			if item.Key.String() != "project_id" {
+				continue
+			}
+			v, ok := item.Value.(*ast.StringNode)
+			if !ok || v.Value == "" {
+				return "", fmt.Errorf("`project_id` must be a non-empty string")
+			}
+			return v.Value, nil
+		}
+	}
+	return "", fmt.Errorf("the `project.project_id` field is required")
+}
+
+func parseRootMapping(reader io.Reader) (*ast.MappingNode, error) {
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, reader); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	root, err := parser.ParseBytes(buf.Bytes(), parser.Mode(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(root.Docs) != 1 {
+		return nil, fmt.Errorf("there should be only one document. Got %d", len(root.Docs))
+	}
+
+	body, ok := root.Docs[0].Body.(*ast.MappingNode)
+	if !ok {
+		return nil, fmt.Errorf("the root node must be of mapping type")
+	}
+	return body, nil
+}
@@ -82,3 +82,60 @@ func TestDetectConfigVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectProjectID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "valid project",
+			input:    validInput,
+			expected: "pid",
+		},
+		{
+			name:     "pages are not read",
+			input:    "version: 2\nproject:\n  project_id: pid\npages:\n  - type: unknown\n",
+			expected: "pid",
+		},
+		{
+			name:        "missing project_id",
+			input:       "version: 2\nproject:\n  name: name\n",
+			expectError: true,
+		},
+		{
+			name:        "project is not a mapping",
+			input:       "version: 2\nproject: pid\n",
+			expectError: true,
+		},
+		{
+			name:        "invalid yaml",
+			input:       invalidYAMLInput,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			projectID, err := DetectProjectID(strings.NewReader(testCase.input))
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if projectID != testCase.expected {
+				t.Fatalf("expected project_id %s, got %s", testCase.expected, projectID)
+			}
+		})
+	}
+}
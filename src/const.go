@@ -1,7 +1,9 @@
 package main
 
 const (
-	FormatText = "text"
-	FormatJSON = "json"
-	FormatTUI  = "tui"
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatTUI   = "tui"
+	FormatTree  = "tree"
+	FormatPlain = "plain"
 )
@@ -3,6 +3,7 @@ package main
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,8 +11,10 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/caarlos0/log"
+	"github.com/toritoritori29/dodo-cli/src/api"
 	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
 )
 
@@ -19,26 +22,21 @@ const (
 	DefaultArchivePath = "dodo.zip"
 	DocsDir            = "docs"
 	BlobsDir           = "blobs"
+	MetadataFileName   = "metadata.json"
 )
 
 type Archive struct {
 	File          *os.File
 	Metadata      *Metadata
+	ManifestID    string // set when the archive only contains the blobs the server asked for
 	shouldCleanUp bool
+	saved         bool // the file is closed and moved by SaveAs
 }
 
 func NewArchive(path string) (*Archive, error) {
 	// Prepare archive file
 	if path == "" {
-		zipFile, err := os.CreateTemp("", DefaultArchivePath)
-		if err != nil {
-			log.Error("failed to create a temporary file")
-			return nil, fmt.Errorf("failed to create a temporary file: %w", err)
-		}
-		return &Archive{
-			File:          zipFile,
-			shouldCleanUp: true,
-		}, nil
+		return NewTemporaryArchive("")
 	}
 
 	zipFile, err := os.Create(path)
@@ -52,7 +50,131 @@ func NewArchive(path string) (*Archive, error) {
 	}, nil
 }
 
+// NewTemporaryArchive creates an archive in dir, or in the default temporary directory if dir is empty.
+// The file is removed on Close unless it is moved with SaveAs.
+func NewTemporaryArchive(dir string) (*Archive, error) {
+	zipFile, err := os.CreateTemp(dir, DefaultArchivePath)
+	if err != nil {
+		log.Error("failed to create a temporary file")
+		return nil, fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	return &Archive{
+		File:          zipFile,
+		shouldCleanUp: true,
+	}, nil
+}
+
+// OpenArchive opens an archive built by `dodo pack` and reads the metadata back out of it.
+// The blobs are not extracted, the archive is uploaded as is.
+func OpenArchive(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the archive file. Path: %s: %w", path, err)
+	}
+	metadata, err := readArchiveMetadata(file)
+	if err != nil {
+		file.Close() //nolint:errcheck
+		return nil, fmt.Errorf("invalid archive file. Path: %s: %w", path, err)
+	}
+	return &Archive{
+		File:          file,
+		Metadata:      metadata,
+		shouldCleanUp: false,
+	}, nil
+}
+
+func readArchiveMetadata(file *os.File) (*Metadata, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat the archive file: %w", err)
+	}
+	reader, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the zip archive: %w", err)
+	}
+
+	var metadata *Metadata
+	blobs := make(map[string]struct{}, len(reader.File))
+	for _, f := range reader.File {
+		if f.Name == MetadataFileName {
+			metadata, err = decodeArchiveMetadata(f)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if filepath.Dir(f.Name) == BlobsDir {
+			blobs[filepath.Base(f.Name)] = struct{}{}
+		}
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("%s is not found in the archive", MetadataFileName)
+	}
+
+	// The archive must be self-contained.
+	for _, blob := range metadata.ListBlobs() {
+		if _, ok := blobs[blob.Hash]; !ok {
+			return nil, fmt.Errorf("the blob of %s is not found in the archive", blob.Filepath)
+		}
+	}
+	return metadata, nil
+}
+
+func decodeArchiveMetadata(f *zip.File) (*Metadata, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s in the archive: %w", MetadataFileName, err)
+	}
+	defer rc.Close() //nolint:errcheck
+
+	metadata := Metadata{}
+	if err := json.NewDecoder(rc).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s in the archive: %w", MetadataFileName, err)
+	}
+	return &metadata, nil
+}
+
+// BlobSizes returns the uncompressed size of every blob in the archive, keyed by hash.
+func (a *Archive) BlobSizes() (map[string]int64, error) {
+	info, err := a.File.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat the archive file: %w", err)
+	}
+	reader, err := zip.NewReader(a.File, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the zip archive: %w", err)
+	}
+	sizes := make(map[string]int64, len(reader.File))
+	for _, f := range reader.File {
+		if filepath.Dir(f.Name) == BlobsDir {
+			sizes[filepath.Base(f.Name)] = int64(f.UncompressedSize64)
+		}
+	}
+	return sizes, nil
+}
+
+// SaveAs closes the archive and moves it to path. Close does nothing after that.
+// The archive should be in the same directory as path, so that it is moved atomically.
+func (a *Archive) SaveAs(path string) error {
+	if err := a.File.Chmod(0o644); err != nil { //nolint:mnd
+		return fmt.Errorf("failed to set the permission of the archive file: %w", err)
+	}
+	if err := a.File.Close(); err != nil {
+		return fmt.Errorf("failed to close the archive file: %w", err)
+	}
+	if err := os.Rename(a.File.Name(), path); err != nil {
+		os.Remove(a.File.Name()) //nolint:errcheck
+		a.saved = true
+		return fmt.Errorf("failed to move the archive file to %s: %w", path, err)
+	}
+	a.saved = true
+	return nil
+}
+
 func (a *Archive) Close() error {
+	if a.saved {
+		return nil
+	}
 	err := a.File.Close()
 	if err != nil {
 		return fmt.Errorf("failed to close the archive file: %w", err)
@@ -66,31 +188,19 @@ func (a *Archive) Close() error {
 }
 
 func (a *Archive) Archive(metadata *Metadata) *appErrors.MultiError {
-	// Archive documents
+	return a.ArchiveBlobs(metadata, metadata.ListBlobs())
+}
+
+// ArchiveBlobs archives only the given blobs together with the metadata.
+// It is used by incremental uploads, where the server already has the other blobs.
+func (a *Archive) ArchiveBlobs(metadata *Metadata, blobs []MetadataBlob) *appErrors.MultiError {
 	zipWriter := zip.NewWriter(a.File)
 
-	// New docs logics
+	// Add documents and assets with the hash name under the `blobs` directory.
 	merr := appErrors.NewMultiError()
-	for _, page := range metadata.Page.ListPageHeader() {
-		if page.Type != PageTypeLeafNode {
-			continue
-		}
-
-		for _, lang := range page.Language {
-			from := lang.Filepath
-			to := filepath.Join(BlobsDir, lang.Hash)
-			if err := addFile(from, to, zipWriter); err != nil {
-				merr.Add(err)
-			}
-		}
-	}
-
-	// Archive assets
-	// Add assets with the hash name under the `blobs` directory.
-	for _, asset := range metadata.Asset {
-		from := asset.Path
-		to := filepath.Join(BlobsDir, filepath.Base(asset.Hash))
-		if err := addFile(from, to, zipWriter); err != nil {
+	for _, blob := range blobs {
+		to := filepath.Join(BlobsDir, blob.Hash)
+		if err := addFile(blob.Filepath, to, zipWriter); err != nil {
 			merr.Add(err)
 		}
 	}
@@ -109,32 +219,58 @@ func (a *Archive) Archive(metadata *Metadata) *appErrors.MultiError {
 	return nil
 }
 
-func (a *Archive) Upload(url string, bearerToken string) (*UploadResponse, error) {
+// Upload sends the archive in a single multipart request.
+// The request is sent again from the beginning if it fails with a retryable error.
+func (a *Archive) Upload(client *api.Client, url string, reporter ProgressReporter) (*UploadResponse, error) {
 	if a.Metadata == nil {
 		return nil, errors.New("metadata is not set. Please call Archive() before Upload()")
 	}
-	req, err := newFileUploadRequest(url, a.Metadata, a.File, bearerToken)
+	info, err := a.File.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create upload request: %w", err)
+		return nil, fmt.Errorf("failed to stat the archive file: %w", err)
 	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
+
+	// Every attempt reads the archive through its own section reader,
+	// because the body of a failed attempt may still be read by its writer goroutine.
+	// The progress is finished only after the last attempt, so that the retries are shown as well.
+	var body *progressReader
+	defer func() {
+		if body != nil {
+			body.Finish()
+		}
+	}()
+	resp, err := client.Do(func() (*http.Request, error) {
+		body = newProgressReader(io.NewSectionReader(a.File, 0, info.Size()), info.Size(), reporter)
+		req, err := newFileUploadRequest(client, url, a.Metadata, a.ManifestID, filepath.Base(a.File.Name()), body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create upload request: %w", err)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error occurred during communication with the server: %w", err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
+	if !api.IsSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("failed to upload file: %w", api.NewError(resp))
+	}
 	data, err := ParseUploadResponse(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse the response: %w", err)
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to upload file: Status %d,  %s", resp.StatusCode, data.Message)
-	}
 	return data, nil
 }
 
+// createArchiveEntry adds an entry with a fixed timestamp, so that the same inputs always produce the same archive.
+func createArchiveEntry(writer *zip.Writer, name string) (io.Writer, error) {
+	return writer.CreateHeader(&zip.FileHeader{ //nolint:wrapcheck
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
+
 func addFile(from, to string, writer *zip.Writer) error {
 	targetFile, err := os.Open(from)
 	if err != nil {
@@ -143,7 +279,7 @@ func addFile(from, to string, writer *zip.Writer) error {
 	defer targetFile.Close() //nolint:errcheck
 
 	log.Debug(fmt.Sprintf("add %s to archive", from))
-	w, err := writer.Create(to)
+	w, err := createArchiveEntry(writer, to)
 	if err != nil {
 		return fmt.Errorf("failed to get zip writer: %w", err)
 	}
@@ -161,7 +297,7 @@ func addMetadata(metadata *Metadata, writer *zip.Writer) error {
 	}
 	log.Debug("add metadata.json to archive")
 
-	w, err := writer.Create("metadata.json")
+	w, err := createArchiveEntry(writer, MetadataFileName)
 	if err != nil {
 		return fmt.Errorf("failed to get zip writer: %w", err)
 	}
@@ -172,57 +308,54 @@ func addMetadata(metadata *Metadata, writer *zip.Writer) error {
 	return nil
 }
 
-func newFileUploadRequest(uri string, metadata *Metadata, zipFile *os.File, bearerToken string) (*http.Request, error) {
-	body := &bytes.Buffer{}
-	// Try to create a new multipart writer in a closure.
-	// This is to ensure that the multipart writer is closed properly.
-	// writer.Close() must be called before pass it to http.NewRequest.
-	// If we break this rule, the request will not be sent properly.
-	writer, err := func() (*multipart.Writer, error) {
-		writer := multipart.NewWriter(body)
-		defer writer.Close() //nolint:errcheck
-
-		// Write metadata
-		serialized, err := metadata.Serialize()
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize metadata: %w", err)
-		}
-		metadataPart, err := writer.CreateFormField("metadata")
-		if err != nil {
-			return nil, fmt.Errorf("failed to create a multipart section: %w", err)
-		}
-		_, err = metadataPart.Write(serialized)
-		if err != nil {
-			return nil, fmt.Errorf("failed to write metadata to the multipart section: %w", err)
-		}
-
-		// Write archived documents
-		filePart, err := writer.CreateFormFile("archive", filepath.Base(zipFile.Name()))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create FormFile: %w", err)
-		}
-
-		_, err = zipFile.Seek(0, 0)
-		if err != nil {
-			return nil, fmt.Errorf("failed to seek the archive file: %w", err)
-		}
-		_, err = io.Copy(filePart, zipFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to copy archive file content to writer: %w", err)
-		}
-		return writer, nil
-	}()
+// newFileUploadRequest creates a multipart request whose body is streamed through a pipe,
+// so that the archive is never loaded into memory as a whole.
+func newFileUploadRequest(client *api.Client, uri string, metadata *Metadata, manifestID, archiveName string, archive io.Reader) (*http.Request, error) {
+	serialized, err := metadata.Serialize()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to serialize metadata: %w", err)
 	}
 
-	log.Debugf("contents size %d", body.Len())
-	req, err := http.NewRequest(http.MethodPost, uri, body)
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	req, err := client.NewRequest(http.MethodPost, uri, pr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a new upload request from body: %w", err)
 	}
-	bearer := "Bearer " + bearerToken
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Authorization", bearer)
+
+	// The body is written while the client sends the request.
+	// If the client stops reading (e.g. on a connection error), the pipe is closed and the writer exits.
+	go func() {
+		err := writeMultipartBody(writer, serialized, manifestID, archiveName, archive)
+		pw.CloseWithError(err) //nolint:errcheck
+	}()
 	return req, nil
 }
+
+func writeMultipartBody(writer *multipart.Writer, metadata []byte, manifestID, archiveName string, archive io.Reader) error {
+	// Write metadata
+	if err := writer.WriteField("metadata", string(metadata)); err != nil {
+		return fmt.Errorf("failed to write metadata to the multipart section: %w", err)
+	}
+
+	// Tell the server which negotiated manifest the partial archive belongs to.
+	if manifestID != "" {
+		if err := writer.WriteField("manifest_id", manifestID); err != nil {
+			return fmt.Errorf("failed to write manifest_id to the multipart section: %w", err)
+		}
+	}
+
+	// Write archived documents
+	filePart, err := writer.CreateFormFile("archive", archiveName)
+	if err != nil {
+		return fmt.Errorf("failed to create FormFile: %w", err)
+	}
+	if _, err := io.Copy(filePart, archive); err != nil {
+		return fmt.Errorf("failed to copy archive file content to writer: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close the multipart writer: %w", err)
+	}
+	return nil
+}
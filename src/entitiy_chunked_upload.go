@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/caarlos0/log"
+	"github.com/toritoritori29/dodo-cli/src/api"
+	"github.com/toritoritori29/dodo-cli/src/utils"
+)
+
+const MiB = 1024 * 1024
+
+// ChunkedUploader uploads an archive in chunks under an upload session.
+// The session is saved locally before the first chunk is sent, so that an interrupted upload
+// can be resumed with `--resume <session id>`.
+type ChunkedUploader struct {
+	Client    *api.Client
+	Endpoint  string
+	ChunkSize int64
+	Store     UploadSessionStore
+	Reporter  ProgressReporter
+}
+
+// Upload starts a new session for the archive and sends every chunk.
+func (u *ChunkedUploader) Upload(archive *Archive) (*UploadResponse, error) {
+	if archive.Metadata == nil {
+		return nil, errors.New("metadata is not set. Please call Archive() before Upload()")
+	}
+	if u.ChunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size: %d", u.ChunkSize)
+	}
+	digest, size, err := utils.HashFile(archive.File.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := createUploadSession(u.Client, u.Endpoint, &CreateUploadSessionRequest{
+		Metadata:   archive.Metadata,
+		ManifestID: archive.ManifestID,
+		Size:       size,
+		ChunkSize:  u.ChunkSize,
+		Digest:     digest,
+	})
+	if err != nil {
+		return nil, err
+	}
+	session := &UploadSession{
+		ID:         resp.SessionID,
+		Endpoint:   u.Endpoint,
+		Digest:     digest,
+		Size:       size,
+		ChunkSize:  u.ChunkSize,
+		ManifestID: archive.ManifestID,
+	}
+	if err := u.Store.Save(session, archive.File); err != nil {
+		return nil, err
+	}
+	log.Debugf("upload session %s started. %d chunks", session.ID, session.Chunks())
+	return u.run(session)
+}
+
+// Resume continues a session saved by a previous run. Only the chunks the server has not received are sent.
+func (u *ChunkedUploader) Resume(sessionID string) (*UploadResponse, error) {
+	session, err := u.Store.Load(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	digest, _, err := utils.HashFile(u.Store.ArchivePath(session.ID))
+	if err != nil {
+		return nil, err
+	}
+	if digest != session.Digest {
+		return nil, fmt.Errorf("the saved archive of the upload session %s is broken", session.ID)
+	}
+	// The session belongs to the endpoint it was created at.
+	u.Endpoint = session.Endpoint
+	log.Infof("resuming the upload session %s", session.ID)
+	return u.run(session)
+}
+
+func (u *ChunkedUploader) run(session *UploadSession) (*UploadResponse, error) {
+	file, err := os.Open(u.Store.ArchivePath(session.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the archive of the upload session: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	status, err := getUploadSession(u.Client, u.Endpoint, session.ID)
+	if err != nil {
+		return nil, u.interrupted(session, err)
+	}
+	received := make(map[int]struct{}, len(status.Received))
+	for _, index := range status.Received {
+		received[index] = struct{}{}
+	}
+
+	started := time.Now()
+	var sent int64
+	progress := func() ProgressEvent {
+		return ProgressEvent{Sent: sent, Total: session.Size, Elapsed: time.Since(started)}
+	}
+	for index := range session.Chunks() {
+		offset, size := session.ChunkRange(index)
+		if _, ok := received[index]; ok {
+			sent += size
+			continue
+		}
+		err := uploadChunk(u.Client, u.Endpoint, session.ID, index, offset, size, session.Size, func() io.Reader {
+			return io.NewSectionReader(file, offset, size)
+		})
+		if err != nil {
+			u.Reporter.Finish(progress())
+			return nil, u.interrupted(session, fmt.Errorf("failed to upload the chunk %d: %w", index, err))
+		}
+		sent += size
+		u.Reporter.Report(progress())
+	}
+	u.Reporter.Finish(progress())
+
+	resp, err := completeUploadSession(u.Client, u.Endpoint, session.ID)
+	if err != nil {
+		return nil, u.interrupted(session, err)
+	}
+	if err := u.Store.Remove(session.ID); err != nil {
+		log.Warnf("failed to clean up the upload session: %v", err)
+	}
+	return resp, nil
+}
+
+func (u *ChunkedUploader) interrupted(session *UploadSession, err error) error {
+	return fmt.Errorf("%w\nthe upload was interrupted. run the command again with `--resume %s` to continue", err, session.ID)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+const (
+	fakeChunkRoute    = "PUT " + fakeUploadPath + UploadSessionPathSuffix + "/{id}/chunks/{index}"
+	fakeCompleteRoute = "POST " + fakeUploadPath + UploadSessionPathSuffix + "/{id}/complete"
+	testChunkSize     = 256
+)
+
+// prepareChunkedUpload archives the upload test project and returns an uploader for the fake server.
+func prepareChunkedUpload(t *testing.T, server *fakeServer, delays *[]time.Duration) (*ChunkedUploader, *Archive) {
+	t.Helper()
+	prepareUploadProject(t)
+	file, err := os.Open(".dodo.yaml")
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck
+	metadata, err := parseConfigFileToMetadata(file, ".dodo.yaml")
+	require.NoError(t, err)
+
+	archive, err := NewArchive("")
+	require.NoError(t, err)
+	t.Cleanup(func() { archive.Close() }) //nolint:errcheck
+	require.Nil(t, archive.Archive(metadata))
+
+	uploader := &ChunkedUploader{
+		Client:    newTestUploadClient(t, server, 2, delays),
+		Endpoint:  server.UploadURL(),
+		ChunkSize: testChunkSize,
+		Store:     NewUploadSessionStore(t.TempDir()),
+		Reporter:  &recordingReporter{},
+	}
+	return uploader, archive
+}
+
+// newTestUploadClient returns a client for the fake server which records the retry delays instead of sleeping.
+func newTestUploadClient(t *testing.T, server *fakeServer, maxRetries int, delays *[]time.Duration) *api.Client {
+	t.Helper()
+	retry := api.RetryPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   time.Minute,
+		Sleep: func(d time.Duration) {
+			*delays = append(*delays, d)
+		},
+	}
+	client, err := NewAPIClient(server.UploadURL(), EnvArgs{APIKey: "test-token"}, api.WithRetry(retry))
+	require.NoError(t, err)
+	return client
+}
+
+func TestChunkedUploadRetry(t *testing.T) {
+	server := newFakeServer(t)
+	delays := []time.Duration{}
+	uploader, archive := prepareChunkedUpload(t, server, &delays)
+	info, err := archive.File.Stat()
+	require.NoError(t, err)
+	chunks := int((info.Size() + testChunkSize - 1) / testChunkSize)
+	require.Greater(t, chunks, 2, "the archive should be split into several chunks")
+
+	// Every kind of transient failure is retried.
+	uploader.Client = newTestUploadClient(t, server, 3, &delays)
+	server.InjectFailures(fakeChunkRoute,
+		fakeFailure{status: http.StatusServiceUnavailable},
+		fakeFailure{status: http.StatusTooManyRequests, retryAfter: "3"},
+		fakeFailure{status: 0},
+	)
+	server.InjectFailures(fakeCompleteRoute, fakeFailure{status: http.StatusBadGateway})
+
+	resp, err := uploader.Upload(archive)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/docs/project_id", resp.DocumentURL)
+	assert.Len(t, server.Received(0), 2)
+	assert.Equal(t, chunks+3, server.Requests(fakeChunkRoute))
+	assert.Len(t, delays, 4)
+	assert.Contains(t, delays, 3*time.Second, "Retry-After should be honored")
+
+	reporter := uploader.Reporter.(*recordingReporter) //nolint:forcetypeassert
+	require.Len(t, reporter.finishes, 1)
+	assert.Equal(t, info.Size(), reporter.finishes[0].Sent)
+
+	// The session is cleaned up after a successful upload.
+	_, err = uploader.Store.Load("session-000")
+	require.ErrorIs(t, err, ErrUploadSessionNotFound)
+}
+
+func TestChunkedUploadResume(t *testing.T) {
+	server := newFakeServer(t)
+	delays := []time.Duration{}
+	uploader, archive := prepareChunkedUpload(t, server, &delays)
+	info, err := archive.File.Stat()
+	require.NoError(t, err)
+	chunks := int((info.Size() + testChunkSize - 1) / testChunkSize)
+
+	// The first chunk succeeds, then the connection keeps dropping until the retries are exhausted.
+	server.InjectFailures(fakeChunkRoute, fakeFailure{pass: true}, fakeFailure{}, fakeFailure{}, fakeFailure{})
+	_, err = uploader.Upload(archive)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--resume session-000")
+	assert.Zero(t, server.Uploads())
+
+	// The session and a copy of the archive are kept for the resume.
+	session, err := uploader.Store.Load("session-000")
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), session.Size)
+
+	before := server.Requests(fakeChunkRoute)
+	// The endpoint is restored from the session.
+	resumer := *uploader
+	resumer.Endpoint = ""
+	resp, err := resumer.Resume("session-000")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/docs/project_id", resp.DocumentURL)
+	assert.Equal(t, chunks-1, server.Requests(fakeChunkRoute)-before, "received chunks should not be sent again")
+	assert.Len(t, server.Received(0), 2)
+
+	_, err = uploader.Store.Load("session-000")
+	require.ErrorIs(t, err, ErrUploadSessionNotFound)
+}
+
+func TestChunkedUploadResumeUnknownSession(t *testing.T) {
+	t.Parallel()
+	uploader := &ChunkedUploader{Store: NewUploadSessionStore(t.TempDir()), Reporter: &recordingReporter{}}
+	_, err := uploader.Resume("../unknown")
+	require.ErrorIs(t, err, ErrUploadSessionNotFound)
+}
+
+func TestUploadSessionChunks(t *testing.T) {
+	t.Parallel()
+	session := UploadSession{Size: 1000, ChunkSize: 300}
+	assert.Equal(t, 4, session.Chunks())
+	offset, size := session.ChunkRange(3)
+	assert.Equal(t, int64(900), offset)
+	assert.Equal(t, int64(100), size)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/tree"
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+// Layout is the page tree of a project deployed on dodo.
+type Layout struct {
+	ProjectID   string   `json:"project_id"`
+	IsPublic    bool     `json:"is_public"`
+	AllowedOrgs []string `json:"allowed_orgs"`
+	Page        Page     `json:"page"`
+}
+
+// LayoutLink is a page of the layout. Path can be passed to `dodo read --path`.
+type LayoutLink struct {
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	Depth    int    `json:"depth"`
+}
+
+func NewLayoutFromAPI(client *api.Client, projectID string) (*Layout, error) {
+	data, err := client.GetLayout(projectID)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	page, err := decodeLayoutPage(data.Layout)
+	if err != nil {
+		return nil, err
+	}
+	return &Layout{
+		ProjectID:   projectID,
+		IsPublic:    data.IsPublic,
+		AllowedOrgs: data.AllowedOrgs,
+		Page:        page,
+	}, nil
+}
+
+// decodeLayoutPage converts the layout of the response into the page tree of metadata.json.
+// The layout is either the root page itself or an object which holds it in `page`.
+func decodeLayoutPage(layout map[string]any) (Page, error) {
+	page := Page{}
+	var source any = layout
+	if root, ok := layout["page"]; ok {
+		source = root
+	}
+	b, err := json.Marshal(source)
+	if err != nil {
+		return page, fmt.Errorf("failed to read the layout: %w", err)
+	}
+	if err := json.Unmarshal(b, &page); err != nil {
+		return page, fmt.Errorf("failed to read the layout: %w", err)
+	}
+	return page, nil
+}
+
+// Links lists the pages in the order of the tree. Nodes without a path, e.g. sections, are skipped.
+func (l *Layout) Links() []LayoutLink {
+	links := []LayoutLink{}
+	var walk func(p *Page, depth int)
+	walk = func(p *Page, depth int) {
+		for _, lang := range p.Language {
+			if lang.Path == "" {
+				continue
+			}
+			links = append(links, LayoutLink{
+				Language: lang.Language,
+				Title:    lang.Title,
+				Path:     lang.Path,
+				Depth:    depth,
+			})
+		}
+		for i := range p.Children {
+			walk(&p.Children[i], depth+1)
+		}
+	}
+	for i := range l.Page.Children {
+		walk(&l.Page.Children[i], 0)
+	}
+	return links
+}
+
+// LanguagePath returns the path of the page at the path in another language.
+// The path can be the one of any language of the page.
+func (l *Layout) LanguagePath(path, language string) (string, error) {
+	page := l.findPage(&l.Page, normalizeDocumentPath(path))
+	if page == nil {
+		return "", fmt.Errorf("the page %s is not found in the layout of %s", path, l.ProjectID)
+	}
+	available := make([]string, 0, len(page.Language))
+	for _, lang := range page.Language {
+		if lang.Language == language && lang.Path != "" {
+			return lang.Path, nil
+		}
+		available = append(available, lang.Language)
+	}
+	return "", fmt.Errorf("the page %s is not available in %s. Available languages: %s", path, language, strings.Join(available, ", "))
+}
+
+func (l *Layout) findPage(p *Page, path string) *Page {
+	for _, lang := range p.Language {
+		if lang.Path != "" && normalizeDocumentPath(lang.Path) == path {
+			return p
+		}
+	}
+	for i := range p.Children {
+		if found := l.findPage(&p.Children[i], path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ReadCommand returns the command which prints the page at the path.
+func (l *Layout) ReadCommand(path string) string {
+	return fmt.Sprintf("dodo read --project-id %s --path %s", l.ProjectID, path)
+}
+
+// Text renders the page tree with box-drawing characters.
+func (l *Layout) Text() string {
+	visibility := "private"
+	if l.IsPublic {
+		visibility = "public"
+	}
+	root := tree.Root(fmt.Sprintf("%s (%s)", l.ProjectID, visibility))
+	for i := range l.Page.Children {
+		root.Child(layoutPageTree(&l.Page.Children[i]))
+	}
+
+	var b strings.Builder
+	b.WriteString(root.String() + "\n")
+	if len(l.AllowedOrgs) > 0 {
+		fmt.Fprintf(&b, "\nAllowed organizations: %s\n", strings.Join(l.AllowedOrgs, ", "))
+	}
+	fmt.Fprintf(&b, "\nRead a page with: %s\n", l.ReadCommand("<path>"))
+	return b.String()
+}
+
+// layoutPageTree labels a node with the title and the path of its first language.
+// The other languages are listed after them.
+func layoutPageTree(p *Page) *tree.Tree {
+	label := p.Type
+	if len(p.Language) > 0 {
+		first := p.Language[0]
+		label = first.Title
+		if first.Path != "" {
+			label += " -> " + first.Path
+		}
+		others := make([]string, 0, len(p.Language)-1)
+		for _, lang := range p.Language[1:] {
+			others = append(others, lang.Language)
+		}
+		if len(others) > 0 {
+			label += fmt.Sprintf(" [%s, %s]", first.Language, strings.Join(others, ", "))
+		}
+	}
+
+	node := tree.Root(label)
+	for i := range p.Children {
+		node.Child(layoutPageTree(&p.Children[i]))
+	}
+	return node
+}
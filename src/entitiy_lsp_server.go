@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/log"
+)
+
+// LSP error codes, in addition to those of JSON-RPC 2.0.
+const lspServerNotInitialized = -32002
+
+// Kinds of the completion items defined by the Language Server Protocol.
+const (
+	lspCompletionKindValue    = 12
+	lspCompletionKindProperty = 10
+	lspCompletionKindFile     = 17
+	lspCompletionKindFolder   = 19
+)
+
+const (
+	lspSeverityError = 1
+	lspTextSyncFull  = 1
+)
+
+// maxLSPMessageSize limits the Content-Length of a message read from the client.
+const maxLSPMessageSize = 16 << 20
+
+// errLSPExit stops the server when the client sends the `exit` notification.
+var errLSPExit = errors.New("exit")
+
+// LSPServer serves the language features of `.dodo.yaml` and the front matter of the markdown files over stdio.
+// Every message is a JSON-RPC 2.0 object preceded by a `Content-Length` header.
+// The server keeps the text of the open documents, so that the diagnostics follow the user as they type.
+type LSPServer struct {
+	name        string
+	version     string
+	documents   map[string]string // text of the open documents, keyed by URI
+	initialized bool
+}
+
+func NewLSPServer(name, version string) *LSPServer {
+	return &LSPServer{
+		name:      name,
+		version:   version,
+		documents: make(map[string]string),
+	}
+}
+
+type jsonRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCompletionItem struct {
+	Label      string       `json:"label"`
+	Kind       int          `json:"kind"`
+	InsertText string       `json:"insertText,omitempty"`
+	TextEdit   *lspTextEdit `json:"textEdit,omitempty"`
+}
+
+type lspMarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type lspHover struct {
+	Contents lspMarkupContent `json:"contents"`
+}
+
+type lspTextDocumentPositionParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+// Serve reads the messages from r until the client sends `exit` or closes the stream, and writes the responses to w.
+func (s *LSPServer) Serve(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	for {
+		body, err := readLSPMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		messages, err := s.handleMessage(body)
+		for _, message := range messages {
+			if err := writeLSPMessage(w, message); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, errLSPExit) {
+			return nil
+		}
+	}
+}
+
+// readLSPMessage reads the headers and returns the body of the next message.
+func readLSPMessage(reader *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 || length > maxLSPMessageSize {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, fmt.Errorf("failed to read the message: %w", err)
+	}
+	return body, nil
+}
+
+func writeLSPMessage(w io.Writer, message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the message: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		return fmt.Errorf("failed to write the message: %w", err)
+	}
+	return nil
+}
+
+// handleMessage returns the messages to send back: the response of a request and the notifications, e.g. diagnostics.
+func (s *LSPServer) handleMessage(body []byte) ([]any, error) {
+	req := jsonRPCRequest{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return []any{newJSONRPCError(nil, jsonRPCParseError, "failed to parse the message: "+err.Error())}, nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return []any{newJSONRPCError(req.ID, jsonRPCInvalidRequest, "the message is not a JSON-RPC 2.0 request")}, nil
+	}
+	if len(req.ID) == 0 {
+		log.Debugf("lsp: received the notification %s", req.Method)
+		return s.handleNotification(req)
+	}
+
+	log.Debugf("lsp: received the request %s", req.Method)
+	if req.Method != "initialize" && !s.initialized {
+		return []any{newJSONRPCError(req.ID, lspServerNotInitialized, "the server is not initialized")}, nil
+	}
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+		return []any{s.handleInitialize(req)}, nil
+	case "shutdown":
+		return []any{&jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage("null")}}, nil
+	case "textDocument/completion":
+		return []any{s.handlePositionRequest(req, func(doc lspDocument, pos lspPosition) any { return doc.Completion(pos) })}, nil
+	case "textDocument/hover":
+		return []any{s.handlePositionRequest(req, func(doc lspDocument, pos lspPosition) any { return doc.Hover(pos) })}, nil
+	case "textDocument/definition":
+		return []any{s.handlePositionRequest(req, func(doc lspDocument, pos lspPosition) any { return doc.Definition(pos) })}, nil
+	default:
+		return []any{newJSONRPCError(req.ID, jsonRPCMethodNotFound, "method not found: "+req.Method)}, nil
+	}
+}
+
+func (s *LSPServer) handleNotification(req jsonRPCRequest) ([]any, error) {
+	params := struct {
+		TextDocument struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"textDocument"`
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+	}{}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			log.Warnf("lsp: invalid params of %s: %v", req.Method, err)
+			return nil, nil
+		}
+	}
+	uri := params.TextDocument.URI
+
+	switch req.Method {
+	case "exit":
+		return nil, errLSPExit
+	case "textDocument/didOpen":
+		s.documents[uri] = params.TextDocument.Text
+		return []any{s.publishDiagnostics(uri)}, nil
+	case "textDocument/didChange":
+		// The server asks for the full text, so the last change holds the whole document.
+		if n := len(params.ContentChanges); n > 0 {
+			s.documents[uri] = params.ContentChanges[n-1].Text
+		}
+		return []any{s.publishDiagnostics(uri)}, nil
+	case "textDocument/didSave":
+		// The config reads the markdown files from the disk. Check the open configs again when a file is saved.
+		messages := []any{}
+		for open := range s.documents {
+			if open == uri || newLSPDocument(open, "").IsConfig() {
+				messages = append(messages, s.publishDiagnostics(open))
+			}
+		}
+		return messages, nil
+	case "textDocument/didClose":
+		delete(s.documents, uri)
+		// Clear the diagnostics of the closed document.
+		return []any{newLSPDiagnosticsNotification(uri, []lspDiagnostic{})}, nil
+	}
+	return nil, nil
+}
+
+func (s *LSPServer) handleInitialize(req jsonRPCRequest) *jsonRPCResponse {
+	return newJSONRPCResult(req.ID, map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync": map[string]any{
+				"openClose": true,
+				"change":    lspTextSyncFull,
+				"save":      true,
+			},
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{":", " ", "/"},
+			},
+			"hoverProvider":      true,
+			"definitionProvider": true,
+		},
+		"serverInfo": map[string]string{
+			"name":    s.name,
+			"version": s.version,
+		},
+	})
+}
+
+func (s *LSPServer) handlePositionRequest(req jsonRPCRequest, handle func(lspDocument, lspPosition) any) *jsonRPCResponse {
+	params := lspTextDocumentPositionParams{}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return newJSONRPCError(req.ID, jsonRPCInvalidParams, "invalid params: "+err.Error())
+	}
+	text, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return newJSONRPCError(req.ID, jsonRPCInvalidParams, "the document is not open: "+params.TextDocument.URI)
+	}
+	// A nil result, e.g. no hover, is encoded as null.
+	return newJSONRPCResult(req.ID, handle(newLSPDocument(params.TextDocument.URI, text), params.Position))
+}
+
+func (s *LSPServer) publishDiagnostics(uri string) *jsonRPCNotification {
+	return newLSPDiagnosticsNotification(uri, newLSPDocument(uri, s.documents[uri]).Diagnostics())
+}
+
+func newLSPDiagnosticsNotification(uri string, diagnostics []lspDiagnostic) *jsonRPCNotification {
+	return &jsonRPCNotification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  map[string]any{"uri": uri, "diagnostics": diagnostics},
+	}
+}
+
+// lspPathFromURI returns the local path of a `file://` URI.
+func lspPathFromURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func lspURIFromPath(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// lspUTF16Length returns the length of s in UTF-16 code units, which LSP uses for the columns.
+func lspUTF16Length(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// lspByteOffset converts a column in UTF-16 code units to a byte offset in the line.
+func lspByteOffset(line string, character int) int {
+	n := 0
+	for i, r := range line {
+		if n >= character {
+			return i
+		}
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return len(line)
+}
+
+// lspLines splits a text into lines, dropping the carriage returns of CRLF.
+func lspLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/caarlos0/log"
+)
+
+// The versions of the Model Context Protocol the server speaks, the latest first.
+var MCPProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"} //nolint: gochecknoglobals
+
+// maxMCPMessageSize limits the size of a message read from the client.
+const maxMCPMessageSize = 16 << 20
+
+// Error codes of JSON-RPC 2.0.
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+)
+
+// MCPToolHandler runs a tool with the arguments sent by the client and returns the text shown to the model.
+type MCPToolHandler func(arguments json.RawMessage) (string, error)
+
+// MCPTool is a tool exposed by the server. InputSchema is the JSON Schema of the arguments.
+type MCPTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	Handler     MCPToolHandler  `json:"-"`
+}
+
+// MCPServer serves tools over the stdio transport of the Model Context Protocol.
+// Every message is a JSON-RPC 2.0 object on a single line.
+type MCPServer struct {
+	name    string
+	version string
+	tools   []MCPTool
+}
+
+func NewMCPServer(name, version string, tools []MCPTool) *MCPServer {
+	return &MCPServer{
+		name:    name,
+		version: version,
+		tools:   tools,
+	}
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mcpTextContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpTextContent `json:"content"`
+	IsError bool             `json:"isError"`
+}
+
+// Serve reads the requests from r until it is closed, and writes the responses to w.
+func (s *MCPServer) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMCPMessageSize)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handleMessage(line)
+		if resp == nil {
+			continue
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write the response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read the request: %w", err)
+	}
+	return nil
+}
+
+// handleMessage returns nil for a notification, as it has no response.
+func (s *MCPServer) handleMessage(line []byte) *jsonRPCResponse {
+	req := jsonRPCRequest{}
+	if err := json.Unmarshal(line, &req); err != nil {
+		return newJSONRPCError(nil, jsonRPCParseError, "failed to parse the message: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return newJSONRPCError(req.ID, jsonRPCInvalidRequest, "the message is not a JSON-RPC 2.0 request")
+	}
+	if len(req.ID) == 0 {
+		log.Debugf("mcp: received the notification %s", req.Method)
+		return nil
+	}
+
+	log.Debugf("mcp: received the request %s", req.Method)
+	switch req.Method {
+	case "initialize":
+		return s.handleInitialize(req)
+	case "ping":
+		return newJSONRPCResult(req.ID, struct{}{})
+	case "tools/list":
+		return newJSONRPCResult(req.ID, map[string]any{"tools": s.tools})
+	case "tools/call":
+		return s.handleToolsCall(req)
+	default:
+		return newJSONRPCError(req.ID, jsonRPCMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+func (s *MCPServer) handleInitialize(req jsonRPCRequest) *jsonRPCResponse {
+	params := struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}{}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newJSONRPCError(req.ID, jsonRPCInvalidParams, "invalid params: "+err.Error())
+		}
+	}
+	// Answer with the version the client asked for if it is supported, or with the latest one.
+	version := MCPProtocolVersions[0]
+	if slices.Contains(MCPProtocolVersions, params.ProtocolVersion) {
+		version = params.ProtocolVersion
+	}
+	return newJSONRPCResult(req.ID, map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools": map[string]any{},
+		},
+		"serverInfo": map[string]string{
+			"name":    s.name,
+			"version": s.version,
+		},
+	})
+}
+
+func (s *MCPServer) handleToolsCall(req jsonRPCRequest) *jsonRPCResponse {
+	params := struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}{}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return newJSONRPCError(req.ID, jsonRPCInvalidParams, "invalid params: "+err.Error())
+	}
+	index := slices.IndexFunc(s.tools, func(t MCPTool) bool { return t.Name == params.Name })
+	if index < 0 {
+		return newJSONRPCError(req.ID, jsonRPCInvalidParams, "unknown tool: "+params.Name)
+	}
+	if len(params.Arguments) == 0 {
+		params.Arguments = json.RawMessage("{}")
+	}
+
+	// A failure of the tool is reported to the model, not as a protocol error.
+	text, err := s.tools[index].Handler(params.Arguments)
+	if err != nil {
+		log.Warnf("mcp: the tool %s failed: %v", params.Name, err)
+		message := err.Error()
+		if hint := errorHint(err); hint != "" {
+			message += "\n" + hint
+		}
+		return newJSONRPCResult(req.ID, mcpToolResult{Content: []mcpTextContent{{Type: "text", Text: message}}, IsError: true})
+	}
+	return newJSONRPCResult(req.ID, mcpToolResult{Content: []mcpTextContent{{Type: "text", Text: text}}})
+}
+
+func newJSONRPCResult(id json.RawMessage, result any) *jsonRPCResponse {
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func newJSONRPCError(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: id, Error: &jsonRPCError{Code: code, Message: message}}
+}
+
+// decodeMCPArguments decodes the arguments of a tool and rejects unknown fields.
+func decodeMCPArguments(arguments json.RawMessage, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(arguments))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
@@ -1,12 +1,10 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"net/url"
 	"strings"
 
-	"github.com/toritoritori29/dodo-cli/src/openapi"
+	"github.com/toritoritori29/dodo-cli/src/api"
 )
 
 type Project struct {
@@ -18,33 +16,14 @@ type Project struct {
 	Slug           string
 }
 
-func NewProjectFromAPI(env *EnvArgs, uri string) ([]Project, error) {
-	req, err := http.NewRequest(http.MethodGet, uri, strings.NewReader(""))
+func NewProjectFromAPI(client *api.Client) ([]Project, error) {
+	projects, err := client.ListProjects()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create a new request from the body: %w", err)
+		return nil, err //nolint:wrapcheck
 	}
-	bearer := "Bearer " + env.BearerToken()
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", bearer)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send a request to the server: %w", err)
-	}
-	defer resp.Body.Close() //nolint:errcheck
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("the server returned non-200 status code: %d", resp.StatusCode)
-	}
-
-	data := openapi.ProjectsGetResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to parse the response: %w", err)
-	}
-
-	result := make([]Project, 0, len(data.Projects))
-	for _, p := range data.Projects {
+	result := make([]Project, 0, len(projects))
+	for _, p := range projects {
 		result = append(result, Project{
 			BaseURL:        p.BaseUrl,
 			IsPublic:       p.IsPublic,
@@ -56,3 +35,33 @@ func NewProjectFromAPI(env *EnvArgs, uri string) ([]Project, error) {
 	}
 	return result, nil
 }
+
+// FindProjectByURL returns the project whose base URL the document URL is under, and the path of the document in the project.
+// The scheme, the query and the fragment of the URL are ignored. If several base URLs match, the longest one wins.
+func FindProjectByURL(projects []Project, documentURL *url.URL) (*Project, string, bool) {
+	var found *Project
+	var foundBase string
+	for i := range projects {
+		base, err := url.Parse(projects[i].BaseURL)
+		if err != nil || base.Host == "" || !strings.EqualFold(base.Host, documentURL.Host) {
+			continue
+		}
+		basePath := strings.TrimRight(base.Path, "/")
+		if documentURL.Path != basePath && !strings.HasPrefix(documentURL.Path, basePath+"/") {
+			continue
+		}
+		if found == nil || len(basePath) > len(foundBase) {
+			found = &projects[i]
+			foundBase = basePath
+		}
+	}
+	if found == nil {
+		return nil, "", false
+	}
+	return found, normalizeDocumentPath(strings.TrimPrefix(documentURL.Path, foundBase)), true
+}
+
+// normalizeDocumentPath makes `guide`, `/guide` and `/guide/` the same path.
+func normalizeDocumentPath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
@@ -9,6 +9,26 @@ import (
 
 var ErrAlreadyHandled = fmt.Errorf("already handled error")
 
+// HandledError is returned once an error has been printed. It keeps the exit code of the process.
+// errors.Is(err, ErrAlreadyHandled) holds for it.
+type HandledError struct {
+	ExitCode int
+}
+
+func NewHandledError(exitCode int) *HandledError {
+	return &HandledError{
+		ExitCode: exitCode,
+	}
+}
+
+func (e *HandledError) Error() string {
+	return ErrAlreadyHandled.Error()
+}
+
+func (e *HandledError) Is(target error) bool {
+	return target == ErrAlreadyHandled //nolint:errorlint
+}
+
 type AppError struct {
 	message string
 }
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+const fakeUploadPath = "/project/upload"
+
+// fakeServer emulates the upload API of dodo-doc.
+// It keeps the blobs it has received, so that consecutive uploads can be verified.
+type fakeServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	blobs     map[string]string // hash -> digest of the contents
+	manifests map[string][]ManifestBlob
+	received  [][]string        // blob names in each uploaded archive
+	metadata  []json.RawMessage // metadata of each upload
+	sessions  map[string]*fakeSession
+	failures  map[string][]fakeFailure // route -> failures to inject in order
+	requests  map[string]int           // route -> number of requests
+}
+
+// fakeSession mirrors CreateUploadSessionRequest, but keeps the metadata as is.
+type fakeSession struct {
+	Metadata   json.RawMessage `json:"metadata"`
+	ManifestID string          `json:"manifest_id"`
+	Size       int64           `json:"size"`
+	ChunkSize  int64           `json:"chunk_size"`
+	Digest     string          `json:"digest"`
+
+	chunks map[int][]byte
+}
+
+// fakeFailure is returned instead of the normal response.
+// A zero status drops the connection without a response, and pass lets the request through.
+type fakeFailure struct {
+	status     int
+	retryAfter string
+	pass       bool
+}
+
+// fakeMetadata picks the fields of the metadata the fake server cares about.
+type fakeMetadata struct {
+	Project struct {
+		ProjectID string `json:"project_id"`
+	} `json:"project"`
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	s := &fakeServer{
+		blobs:     map[string]string{},
+		manifests: map[string][]ManifestBlob{},
+		sessions:  map[string]*fakeSession{},
+		failures:  map[string][]fakeFailure{},
+		requests:  map[string]int{},
+	}
+	mux := http.NewServeMux()
+	s.route(mux, "POST "+fakeUploadPath+ManifestPathSuffix, s.handleManifest)
+	s.route(mux, "POST "+fakeUploadPath, s.handleUpload)
+	s.route(mux, "POST "+fakeUploadPath+UploadSessionPathSuffix, s.handleCreateSession)
+	s.route(mux, "GET "+fakeUploadPath+UploadSessionPathSuffix+"/{id}", s.handleGetSession)
+	s.route(mux, "PUT "+fakeUploadPath+UploadSessionPathSuffix+"/{id}/chunks/{index}", s.handleChunk)
+	s.route(mux, "POST "+fakeUploadPath+UploadSessionPathSuffix+"/{id}/complete", s.handleComplete)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// route registers the handler behind the authorization check and the failure injection.
+func (s *fakeServer) route(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(w, r) {
+			return
+		}
+		if s.injectFailure(w, pattern) {
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// InjectFailures makes the next requests to the route fail in order.
+// The route is the pattern given to route(), e.g. "PUT /project/upload/sessions/{id}/chunks/{index}".
+func (s *fakeServer) InjectFailures(route string, failures ...fakeFailure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[route] = append(s.failures[route], failures...)
+}
+
+// Requests returns the number of requests the route has received, including the failed ones.
+func (s *fakeServer) Requests(route string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[route]
+}
+
+func (s *fakeServer) injectFailure(w http.ResponseWriter, route string) bool {
+	s.mu.Lock()
+	s.requests[route]++
+	failures := s.failures[route]
+	if len(failures) == 0 {
+		s.mu.Unlock()
+		return false
+	}
+	failure := failures[0]
+	s.failures[route] = failures[1:]
+	s.mu.Unlock()
+
+	if failure.pass {
+		return false
+	}
+	if failure.status == 0 {
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err == nil {
+			conn.Close() //nolint:errcheck
+		}
+		return true
+	}
+	if failure.retryAfter != "" {
+		w.Header().Set("Retry-After", failure.retryAfter)
+	}
+	writeFakeJSON(w, failure.status, map[string]any{"status": "error", "message": "injected failure"})
+	return true
+}
+
+func (s *fakeServer) UploadURL() string {
+	return s.URL + fakeUploadPath
+}
+
+// Received returns the blob names contained in the n-th uploaded archive.
+func (s *fakeServer) Received(n int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received[n]
+}
+
+func (s *fakeServer) Uploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.received)
+}
+
+func (s *fakeServer) handleManifest(w http.ResponseWriter, r *http.Request) {
+	manifest := struct {
+		Blobs []ManifestBlob `json:"blobs"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&manifest); err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	missing := []string{}
+	for _, blob := range manifest.Blobs {
+		if _, ok := s.blobs[blob.Hash]; !ok {
+			missing = append(missing, blob.Hash)
+		}
+	}
+	manifestID := fmt.Sprintf("manifest-%03d", len(s.manifests))
+	s.manifests[manifestID] = manifest.Blobs
+	writeFakeJSON(w, http.StatusOK, ManifestResponse{
+		Status:     "success",
+		ManifestID: manifestID,
+		Missing:    missing,
+	})
+}
+
+func (s *fakeServer) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": err.Error()})
+		return
+	}
+	file, _, err := r.FormFile("archive")
+	if err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "archive is missing"})
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": err.Error()})
+		return
+	}
+	s.publish(w, json.RawMessage(r.FormValue("metadata")), r.FormValue("manifest_id"), data)
+}
+
+func (s *fakeServer) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	session := &fakeSession{chunks: map[int][]byte{}}
+	if err := json.NewDecoder(r.Body).Decode(session); err != nil || session.ChunkSize <= 0 {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "invalid session"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := fmt.Sprintf("session-%03d", len(s.sessions))
+	s.sessions[id] = session
+	writeFakeJSON(w, http.StatusOK, UploadSessionResponse{Status: "success", SessionID: id})
+}
+
+func (s *fakeServer) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[r.PathValue("id")]
+	if !ok {
+		writeFakeJSON(w, http.StatusNotFound, map[string]any{"status": "error", "message": "unknown session"})
+		return
+	}
+	received := []int{}
+	for index := range session.chunks {
+		received = append(received, index)
+	}
+	writeFakeJSON(w, http.StatusOK, UploadSessionResponse{Status: "success", SessionID: r.PathValue("id"), Received: received})
+}
+
+func (s *fakeServer) handleChunk(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "invalid chunk index"})
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[r.PathValue("id")]
+	if !ok {
+		writeFakeJSON(w, http.StatusNotFound, map[string]any{"status": "error", "message": "unknown session"})
+		return
+	}
+	offset := int64(index) * session.ChunkSize
+	expected := fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(data))-1, session.Size)
+	if r.Header.Get("Content-Range") != expected {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "invalid Content-Range"})
+		return
+	}
+	session.chunks[index] = data
+	writeFakeJSON(w, http.StatusOK, UploadSessionResponse{Status: "success", SessionID: r.PathValue("id")})
+}
+
+func (s *fakeServer) handleComplete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	session, ok := s.sessions[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeFakeJSON(w, http.StatusNotFound, map[string]any{"status": "error", "message": "unknown session"})
+		return
+	}
+	data := []byte{}
+	for index := 0; int64(len(data)) < session.Size; index++ {
+		chunk, ok := session.chunks[index]
+		if !ok {
+			s.mu.Unlock()
+			writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "missing chunk"})
+			return
+		}
+		data = append(data, chunk...)
+	}
+	s.mu.Unlock()
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != session.Digest {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "digest mismatch"})
+		return
+	}
+	s.publish(w, session.Metadata, session.ManifestID, data)
+}
+
+// publish stores the blobs of the archive and responds with the document URL.
+func (s *fakeServer) publish(w http.ResponseWriter, rawMetadata json.RawMessage, manifestID string, data []byte) {
+	metadata := fakeMetadata{}
+	if err := json.Unmarshal(rawMetadata, &metadata); err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "invalid metadata"})
+		return
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "invalid archive"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if manifestID != "" {
+		if _, ok := s.manifests[manifestID]; !ok {
+			writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "unknown manifest"})
+			return
+		}
+	}
+
+	names := []string{}
+	for _, f := range zr.File {
+		if path.Dir(f.Name) != BlobsDir {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": err.Error()})
+			return
+		}
+		hasher := sha256.New()
+		_, err = io.Copy(hasher, rc)
+		rc.Close() //nolint:errcheck
+		if err != nil {
+			writeFakeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": err.Error()})
+			return
+		}
+		name := path.Base(f.Name)
+		s.blobs[name] = hex.EncodeToString(hasher.Sum(nil))
+		names = append(names, name)
+	}
+	s.received = append(s.received, names)
+	s.metadata = append(s.metadata, rawMetadata)
+	writeFakeJSON(w, http.StatusOK, UploadResponse{
+		Status:      "success",
+		DocumentURL: s.URL + "/docs/" + metadata.Project.ProjectID,
+	})
+}
+
+func (s *fakeServer) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") == "Bearer test-token" {
+		return true
+	}
+	writeFakeJSON(w, http.StatusUnauthorized, map[string]any{"status": "error", "message": "unauthorized"})
+	return false
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body) //nolint:errcheck,errchkjson
+}
@@ -26,16 +26,26 @@ func main() {
 	rootCmd.AddCommand(CreateInitCmd())
 	rootCmd.AddCommand(CreateUploadCmd())
 	rootCmd.AddCommand(CreatePreviewCmd())
+	rootCmd.AddCommand(CreatePackCmd())
+	rootCmd.AddCommand(CreateInspectCmd())
+	rootCmd.AddCommand(CreateServeCmd())
 	rootCmd.AddCommand(CreateVersionCmd())
 	rootCmd.AddCommand(CreateTouchCmd())
 	rootCmd.AddCommand(CreateCheckCmd())
 	rootCmd.AddCommand(CreateSearchCmd())
 	rootCmd.AddCommand(CreateDocCmd())
 	rootCmd.AddCommand(CreateReadCmd())
+	rootCmd.AddCommand(CreateLayoutCmd())
+	rootCmd.AddCommand(CreateDiffCmd())
+	rootCmd.AddCommand(CreatePullCmd())
+	rootCmd.AddCommand(CreateMCPCmd())
+	rootCmd.AddCommand(CreateMigrateCmd())
+	rootCmd.AddCommand(CreateSchemaCmd())
+	rootCmd.AddCommand(CreateLSPCmd())
+	rootCmd.AddCommand(CreateFmtCmd())
 
 	defaultPrinter := NewErrorPrinter(ErrorLevel)
 	if err := rootCmd.Execute(); err != nil {
-		defaultPrinter.HandleError(err) //nolint:errcheck
-		os.Exit(1)
+		os.Exit(ExitCode(defaultPrinter.HandleError(err)))
 	}
 }
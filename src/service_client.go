@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+// UploadTimeout limits a single upload request. It is longer than the default, as the body can be large.
+const UploadTimeout = 10 * time.Minute
+
+// UserAgent identifies the CLI and its version to the server.
+func UserAgent() string {
+	return api.DefaultUserAgent + "/" + strings.TrimSpace(Version)
+}
+
+// NewAPIClient creates the client every command uses to talk to the dodo API server.
+// The credentials and the User-Agent are set here, options can override the rest.
+func NewAPIClient(baseURL string, env EnvArgs, options ...api.Option) (*api.Client, error) {
+	defaults := []api.Option{
+		api.WithBearerToken(env.BearerToken()),
+		api.WithUserAgent(UserAgent()),
+	}
+	client, err := api.NewClient(baseURL, append(defaults, options...)...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/toritoritori29/dodo-cli/src/config"
+	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
+)
+
+// The language features of `.dodo.yaml` and the front matter of the markdown files, used by LSPServer.
+// The config is checked by the same parser as `dodo upload`, so the diagnostics match what CI reports.
+
+const lspDiagnosticSource = "dodo"
+
+var (
+	// lspKeyLinePattern matches `key: value` and `- key: value`. The value may be empty.
+	lspKeyLinePattern = regexp.MustCompile(`^(\s*)(-\s+)?([A-Za-z_][A-Za-z0-9_-]*)\s*:(?:\s+(.*))?$`)
+	// lspKeyPrefixPattern matches a key being typed, e.g. `  - fil`.
+	lspKeyPrefixPattern = regexp.MustCompile(`^(\s*)(-\s+)?([A-Za-z_]*)$`)
+)
+
+var (
+	lspRootKeys        = []string{"version", "project", "pages", "assets", "annotation"}
+	lspProjectKeys     = []string{"project_id", "name", "description", "version", "logo", "repository", "default_language"}
+	lspPageTypesV2     = []string{config.ConfigPageTypeMarkdownV2, config.ConfigPageTypeMatchV2, config.ConfigPageTypeDirectoryV2, config.ConfigPageTypeSectionV2, config.ConfigPageTypeIncludeV2}
+	lspPageKeysV1      = []string{"markdown", "title", "path", "description", "match", "sort_key", "sort_order", "directory", "children"}
+	lspLangPageKeys    = []string{"filepath", "title", "link", "description"}
+	lspLangTitleKeys   = []string{"title", "description"}
+	lspFrontMatterKeys = []string{"title", "link", "description", "group", "lang", "created_at", "updated_at"}
+)
+
+// lspPageKeysV2 lists the keys of a page of each type.
+var lspPageKeysV2 = map[string][]string{
+	config.ConfigPageTypeMarkdownV2:  {"type", "filepath", "title", "link", "description", "lang"},
+	config.ConfigPageTypeMatchV2:     {"type", "pattern", "sort_key", "sort_order"},
+	config.ConfigPageTypeDirectoryV2: {"type", "title", "lang", "children"},
+	config.ConfigPageTypeSectionV2:   {"type", "title", "description", "lang", "children"},
+	config.ConfigPageTypeIncludeV2:   {"type", "file"},
+}
+
+// lspValues lists the values of the keys that take one of a few values.
+var lspValues = map[string][]string{
+	"sort_order": {"asc", "desc"},
+}
+
+// lspDocument is a document open in the editor. The text may differ from the file on the disk.
+type lspDocument struct {
+	uri   string
+	path  string
+	lines []string
+	text  string
+}
+
+func newLSPDocument(uri, text string) lspDocument {
+	return lspDocument{
+		uri:   uri,
+		path:  lspPathFromURI(uri),
+		lines: lspLines(text),
+		text:  text,
+	}
+}
+
+func (d lspDocument) IsConfig() bool {
+	name := filepath.Base(d.path)
+	return name == ".dodo.yaml" || name == ".dodo.yml" || strings.HasSuffix(name, ".dodo.yaml")
+}
+
+func (d lspDocument) IsMarkdown() bool {
+	return strings.EqualFold(filepath.Ext(d.path), ".md")
+}
+
+// rootDir returns the directory the paths in the config are relative to.
+func (d lspDocument) rootDir() string {
+	return filepath.Dir(d.path)
+}
+
+func (d lspDocument) version() int {
+	version, err := config.DetectConfigVersion(strings.NewReader(d.text))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func (d lspDocument) line(row int) string {
+	if row < 0 || row >= len(d.lines) {
+		return ""
+	}
+	return d.lines[row]
+}
+
+// Diagnostics -----------------------------------------------------------------
+
+func (d lspDocument) Diagnostics() []lspDiagnostic {
+	var errs []error
+	switch {
+	case d.path == "":
+	case d.IsConfig():
+		errs = d.configErrors()
+	case d.IsMarkdown():
+		if _, err := config.NewFrontMatterFromReader(strings.NewReader(d.text)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	diagnostics := []lspDiagnostic{}
+	for _, err := range errs {
+		diagnostics = append(diagnostics, d.newDiagnostic(err))
+	}
+	return diagnostics
+}
+
+// configErrors parses the config and builds the page tree as `dodo upload` does, and returns every error found.
+func (d lspDocument) configErrors() []error {
+	version, err := config.DetectConfigVersion(strings.NewReader(d.text))
+	if err != nil {
+		return []error{err}
+	}
+
+	switch version {
+	case 1:
+		state := config.NewParseStateV1(d.path, d.rootDir())
+		if _, err := config.ParseConfigV1(state, strings.NewReader(d.text)); err != nil {
+			return lspFlattenErrors(err)
+		}
+		return nil
+	case 2:
+		state := config.NewParseStateV2(d.path, d.rootDir())
+		conf, err := config.ParseConfigV2(state, strings.NewReader(d.text))
+		if err != nil {
+			return lspFlattenErrors(err)
+		}
+		root, merr := CreatePageTreeV2(conf, d.rootDir())
+		if merr != nil {
+			return lspFlattenErrors(merr)
+		}
+		if merr := root.IsValid(conf.Project.GetDefaultLanguageOrFallback()); merr != nil {
+			return lspFlattenErrors(merr)
+		}
+		return nil
+	default:
+		return []error{fmt.Errorf("unsupported config version: %d", version)}
+	}
+}
+
+// lspFlattenErrors expands the errors collected in a MultiError, so that each gets its own diagnostic.
+func lspFlattenErrors(err error) []error {
+	var merr *appErrors.MultiError
+	if !errors.As(err, &merr) {
+		return []error{err}
+	}
+	errs := []error{}
+	for _, e := range merr.Errors() {
+		errs = append(errs, lspFlattenErrors(e)...)
+	}
+	return errs
+}
+
+// newDiagnostic places the error at the node it refers to. Errors without a position go to the first line.
+func (d lspDocument) newDiagnostic(err error) lspDiagnostic {
+	row, column, message := 0, 0, err.Error()
+
+	var parseErr *appErrors.ParseError
+	var syntaxErr *yaml.SyntaxError
+	switch {
+	case errors.As(err, &parseErr) && parseErr.Filepath != d.path:
+		// The error is in an included file. Keep its path and line in the message.
+		message = parseErr.Error()
+	case errors.As(err, &parseErr):
+		message = parseErr.Message
+		if parseErr.Node != nil && parseErr.Node.GetToken() != nil {
+			row = parseErr.Node.GetToken().Position.Line - 1
+			column = parseErr.Node.GetToken().Position.Column - 1
+		}
+	case errors.As(err, &syntaxErr):
+		message = syntaxErr.Message
+		if syntaxErr.Token != nil {
+			row = syntaxErr.Token.Position.Line - 1
+			column = syntaxErr.Token.Position.Column - 1
+		}
+	}
+	row = max(0, min(row, len(d.lines)-1))
+
+	// The columns of the YAML parser count the characters, whereas LSP counts UTF-16 code units.
+	line := []rune(d.line(row))
+	column = max(0, min(column, len(line)))
+	return lspDiagnostic{
+		Range: lspRange{
+			Start: lspPosition{Line: row, Character: lspUTF16Length(string(line[:column]))},
+			End:   lspPosition{Line: row, Character: lspUTF16Length(string(line))},
+		},
+		Severity: lspSeverityError,
+		Source:   lspDiagnosticSource,
+		Message:  message,
+	}
+}
+
+// YAML context ----------------------------------------------------------------
+
+// lspYAMLLine is a line of the config holding a key.
+type lspYAMLLine struct {
+	row    int
+	column int // the column of the key, after the indent and `- `
+	item   bool
+	key    string
+	value  string
+}
+
+func (d lspDocument) parseLine(row int) (lspYAMLLine, bool) {
+	m := lspKeyLinePattern.FindStringSubmatch(d.line(row))
+	if m == nil {
+		return lspYAMLLine{}, false
+	}
+	return lspYAMLLine{
+		row:    row,
+		column: len(m[1]) + len(m[2]),
+		item:   m[2] != "",
+		key:    m[3],
+		value:  lspScalar(m[4]),
+	}, true
+}
+
+// lspScalar strips the comment and the quotes of a plain scalar.
+func lspScalar(value string) string {
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return value
+}
+
+// parents returns the keys enclosing a key at the given position, the nearest first.
+func (d lspDocument) parents(row, column int) []lspYAMLLine {
+	stack := []lspYAMLLine{}
+	for r := range row {
+		line, ok := d.parseLine(r)
+		if !ok {
+			continue
+		}
+		for len(stack) > 0 && stack[len(stack)-1].column >= line.column {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, line)
+	}
+	for len(stack) > 0 && stack[len(stack)-1].column >= column {
+		stack = stack[:len(stack)-1]
+	}
+
+	parents := make([]lspYAMLLine, 0, len(stack))
+	for i := len(stack) - 1; i >= 0; i-- {
+		parents = append(parents, stack[i])
+	}
+	return parents
+}
+
+// siblings returns the values of the keys in the same mapping as a key at the given position.
+// A `- ` starts a new mapping, i.e. a new page.
+func (d lspDocument) siblings(row, column int, item bool) map[string]string {
+	siblings := make(map[string]string)
+	if !item {
+		for r := row - 1; r >= 0; r-- {
+			line, ok := d.parseLine(r)
+			if !ok || line.column > column {
+				continue
+			}
+			if line.column < column {
+				break
+			}
+			siblings[line.key] = line.value
+			if line.item {
+				break
+			}
+		}
+	}
+	for r := row + 1; r < len(d.lines); r++ {
+		line, ok := d.parseLine(r)
+		if !ok || line.column > column {
+			continue
+		}
+		if line.column < column || line.item {
+			break
+		}
+		siblings[line.key] = line.value
+	}
+	return siblings
+}
+
+// pageType returns the type of the page holding the `lang` key, or of the page itself.
+func (d lspDocument) pageType(parents []lspYAMLLine, siblings map[string]string) string {
+	if len(parents) >= 2 && parents[1].key == config.ConfigPageV2KeyLang {
+		lang := parents[1]
+		return d.siblings(lang.row, lang.column, lang.item)[config.ConfigPageV2KeyType]
+	}
+	return siblings[config.ConfigPageV2KeyType]
+}
+
+func lspIsPageList(parents []lspYAMLLine) bool {
+	return len(parents) > 0 && (parents[0].key == "pages" || parents[0].key == "children")
+}
+
+// Completion ------------------------------------------------------------------
+
+func (d lspDocument) Completion(pos lspPosition) []lspCompletionItem {
+	line := d.line(pos.Line)
+	prefix := line[:lspByteOffset(line, pos.Character)]
+	switch {
+	case d.IsConfig():
+		return d.configCompletion(pos, prefix)
+	case d.IsMarkdown():
+		return d.frontMatterCompletion(pos, prefix)
+	}
+	return []lspCompletionItem{}
+}
+
+func (d lspDocument) configCompletion(pos lspPosition, prefix string) []lspCompletionItem {
+	if m := lspKeyLinePattern.FindStringSubmatchIndex(prefix); m != nil {
+		key := prefix[m[6]:m[7]]
+		valueStart := len(prefix)
+		if m[8] >= 0 {
+			valueStart = m[8]
+		}
+		return d.valueCompletion(pos, key, prefix, valueStart)
+	}
+
+	m := lspKeyPrefixPattern.FindStringSubmatch(prefix)
+	if m == nil {
+		return []lspCompletionItem{}
+	}
+	column := len(m[1]) + len(m[2])
+	item := m[2] != ""
+	parents := d.parents(pos.Line, column)
+	siblings := d.siblings(pos.Line, column, item)
+
+	var keys []string
+	switch {
+	case len(parents) == 0:
+		keys = lspRootKeys
+	case parents[0].key == "project":
+		keys = lspProjectKeys
+	case lspIsPageList(parents) && d.version() == 1:
+		keys = lspPageKeysV1
+	case lspIsPageList(parents):
+		if pageType, ok := siblings[config.ConfigPageV2KeyType]; ok {
+			keys = lspPageKeysV2[pageType]
+			// A page with `lang` holds its title and file in the entries of each language.
+			if _, ok := siblings[config.ConfigPageV2KeyLang]; ok {
+				keys = []string{config.ConfigPageV2KeyType, config.ConfigPageV2KeyLang, config.ConfigPageV2KeyChildren}
+			}
+		} else {
+			keys = []string{config.ConfigPageV2KeyType}
+		}
+	case len(parents) >= 2 && parents[1].key == config.ConfigPageV2KeyLang:
+		keys = lspLangTitleKeys
+		if d.pageType(parents, siblings) == config.ConfigPageTypeMarkdownV2 {
+			keys = lspLangPageKeys
+		}
+	}
+
+	items := []lspCompletionItem{}
+	for _, key := range keys {
+		if _, ok := siblings[key]; ok {
+			continue
+		}
+		items = append(items, lspCompletionItem{Label: key, Kind: lspCompletionKindProperty, InsertText: key + ": "})
+	}
+	return items
+}
+
+func (d lspDocument) valueCompletion(pos lspPosition, key, prefix string, valueStart int) []lspCompletionItem {
+	// Skip the opening quote, so that the completion keeps it.
+	if valueStart < len(prefix) && (prefix[valueStart] == '"' || prefix[valueStart] == '\'') {
+		valueStart++
+	}
+
+	var values []string
+	switch key {
+	case config.ConfigPageV2KeyType:
+		column := strings.Index(prefix, key)
+		if lspIsPageList(d.parents(pos.Line, column)) {
+			values = lspPageTypesV2
+		}
+	case config.ConfigPageV2KeySortKey:
+		values = []string{"title"}
+		if d.version() == 1 {
+			values = []string{"title", config.ConfigPageKeyCreatedAt, config.ConfigPageKeyUpdatedAt}
+		}
+	case config.ConfigPageV2KeyFilepath, config.ConfigPageKeyMarkdown:
+		return d.pathCompletion(pos, prefix[valueStart:], valueStart, ".md")
+	case config.ConfigPageV2KeyFile:
+		return d.pathCompletion(pos, prefix[valueStart:], valueStart, ".yaml")
+	case config.ConfigPageV2KeyPattern, config.ConfigPageMatchKeyMatch, "logo":
+		return d.pathCompletion(pos, prefix[valueStart:], valueStart, "")
+	default:
+		values = lspValues[key]
+	}
+
+	items := []lspCompletionItem{}
+	for _, value := range values {
+		items = append(items, lspCompletionItem{Label: value, Kind: lspCompletionKindValue})
+	}
+	return items
+}
+
+// pathCompletion lists the entries of the directory being typed. Only the files with ext are listed if ext is given.
+func (d lspDocument) pathCompletion(pos lspPosition, value string, valueStart int, ext string) []lspCompletionItem {
+	items := []lspCompletionItem{}
+	if d.path == "" {
+		return items
+	}
+	dir := value[:strings.LastIndex(value, "/")+1]
+	entries, err := os.ReadDir(filepath.Join(d.rootDir(), filepath.FromSlash(dir)))
+	if err != nil {
+		return items
+	}
+
+	line := d.line(pos.Line)
+	editRange := lspRange{
+		Start: lspPosition{Line: pos.Line, Character: lspUTF16Length(line[:valueStart])},
+		End:   pos,
+	}
+	showHidden := strings.HasPrefix(value[len(dir):], ".")
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") && !showHidden {
+			continue
+		}
+		kind := lspCompletionKindFile
+		if entry.IsDir() {
+			name += "/"
+			kind = lspCompletionKindFolder
+		} else if ext != "" && !strings.EqualFold(filepath.Ext(name), ext) {
+			continue
+		}
+		items = append(items, lspCompletionItem{
+			Label:    name,
+			Kind:     kind,
+			TextEdit: &lspTextEdit{Range: editRange, NewText: dir + name},
+		})
+	}
+	return items
+}
+
+// frontMatterCompletion completes the keys of the front matter between the `---` lines at the top of a markdown.
+func (d lspDocument) frontMatterCompletion(pos lspPosition, prefix string) []lspCompletionItem {
+	items := []lspCompletionItem{}
+	if pos.Line == 0 || strings.TrimSpace(d.line(0)) != config.FrontMatterStart || strings.Contains(prefix, ":") {
+		return items
+	}
+	present := make(map[string]bool)
+	for row := 1; row < len(d.lines); row++ {
+		if strings.TrimSpace(d.lines[row]) == config.FrontMatterEnd {
+			if row <= pos.Line {
+				return items
+			}
+			break
+		}
+		if line, ok := d.parseLine(row); ok && row != pos.Line {
+			present[line.key] = true
+		}
+	}
+	for _, key := range lspFrontMatterKeys {
+		if !present[key] {
+			items = append(items, lspCompletionItem{Label: key, Kind: lspCompletionKindProperty, InsertText: key + ": "})
+		}
+	}
+	return items
+}
+
+// Hover -----------------------------------------------------------------------
+
+func (d lspDocument) Hover(pos lspPosition) *lspHover {
+	line, ok := d.parseLine(pos.Line)
+	if !ok || !d.IsConfig() || d.path == "" || line.value == "" {
+		return nil
+	}
+
+	var contents string
+	switch line.key {
+	case config.ConfigPageV2KeyFilepath, config.ConfigPageKeyMarkdown:
+		contents = d.pageHover(line)
+	case config.ConfigPageV2KeyPattern, config.ConfigPageMatchKeyMatch:
+		contents = d.patternHover(line)
+	default:
+		return nil
+	}
+	return &lspHover{Contents: lspMarkupContent{Kind: "markdown", Value: contents}}
+}
+
+// pageHover shows the title and the link of the page. The values in the config take precedence over the front matter.
+func (d lspDocument) pageHover(line lspYAMLLine) string {
+	matter, err := config.NewFrontMatterFromMarkdown(filepath.Join(d.rootDir(), line.value))
+	if err != nil {
+		return fmt.Sprintf("Cannot read `%s`: %v", line.value, err)
+	}
+
+	siblings := d.siblings(line.row, line.column, line.item)
+	title := lspFirstNonEmpty(siblings[config.ConfigPageV2KeyTitle], matter.Title)
+	link := lspFirstNonEmpty(siblings[config.ConfigPageV2KeyLink], siblings[config.ConfigPageKeyPath], matter.Link)
+	description := lspFirstNonEmpty(siblings[config.ConfigPageV2KeyDesc], matter.Description)
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "**%s**\n\n", lspFirstNonEmpty(title, "(no title)"))
+	fmt.Fprintf(&b, "Link: `%s`\n", lspFirstNonEmpty(link, "(no link)"))
+	if description != "" {
+		fmt.Fprintf(&b, "\n%s\n", description)
+	}
+	return b.String()
+}
+
+func (d lspDocument) patternHover(line lspYAMLLine) string {
+	matches, err := d.patternMatches(line.value)
+	if err != nil {
+		return err.Error()
+	}
+	if len(matches) == 0 {
+		return fmt.Sprintf("No files match `%s`", line.value)
+	}
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%d files match `%s`\n\n", len(matches), line.value)
+	for _, match := range matches {
+		rel, err := filepath.Rel(d.rootDir(), match)
+		if err != nil {
+			rel = match
+		}
+		fmt.Fprintf(&b, "- %s\n", filepath.ToSlash(rel))
+	}
+	return b.String()
+}
+
+func (d lspDocument) patternMatches(pattern string) ([]string, error) {
+	matches, err := config.ConfigAssetV2(pattern).List(d.rootDir())
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func lspFirstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Definition ------------------------------------------------------------------
+
+func (d lspDocument) Definition(pos lspPosition) []lspLocation {
+	line, ok := d.parseLine(pos.Line)
+	if !ok || !d.IsConfig() || d.path == "" || line.value == "" {
+		return nil
+	}
+
+	var paths []string
+	switch line.key {
+	case config.ConfigPageV2KeyFilepath, config.ConfigPageKeyMarkdown, config.ConfigPageV2KeyFile:
+		path := filepath.Join(d.rootDir(), line.value)
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+		paths = []string{path}
+	case config.ConfigPageV2KeyPattern, config.ConfigPageMatchKeyMatch:
+		matches, err := d.patternMatches(line.value)
+		if err != nil {
+			return nil
+		}
+		paths = matches
+	}
+
+	locations := []lspLocation{}
+	for _, path := range paths {
+		locations = append(locations, lspLocation{URI: lspURIFromPath(path)})
+	}
+	return locations
+}
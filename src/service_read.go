@@ -1,46 +1,70 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 
-	"github.com/toritoritori29/dodo-cli/src/openapi"
+	"github.com/caarlos0/log"
+	"github.com/toritoritori29/dodo-cli/src/api"
 )
 
-func sendReadDocumentRequest(env *EnvArgs, endpoint Endpoint, slug, path string) (string, error) {
-	req, err := http.NewRequest(http.MethodGet, endpoint.DocumentURL(slug, path), strings.NewReader(""))
+func sendReadDocumentRequest(client *api.Client, slug, path string) (string, error) {
+	data, err := client.GetDocument(slug, path)
 	if err != nil {
-		return "", fmt.Errorf("failed to create a new request from the body: %w", err)
+		return "", err //nolint:wrapcheck
 	}
-	bearer := "Bearer " + env.BearerToken()
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", bearer)
+	return *data.Markdown, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// resolveDocumentURL returns the project slug and the document path of a page URL.
+// The URL is matched against the base URLs of the user's projects, so that custom domains and local servers work.
+// The anchor and the query are ignored, and a language prefix such as `/ja/` is kept as a part of the path.
+func resolveDocumentURL(client *api.Client, documentURL string) (string, string, error) {
+	u, err := parseDocumentURL(documentURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to send a request to the server: %w", err)
+		return "", "", err
+	}
+	// The projects are optional here. The URL on the default domain can be resolved without them.
+	projects, projectsErr := NewProjectFromAPI(client)
+	if projectsErr != nil {
+		log.Debugf("failed to list the projects to resolve the URL: %v", projectsErr)
+	}
+	if project, path, ok := FindProjectByURL(projects, u); ok {
+		log.Debugf("The URL %s belongs to the project %s", documentURL, project.Slug)
+		return project.Slug, path, nil
 	}
-	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("the server returned non-200 status code: %d", resp.StatusCode)
+	// Fall back to the default domain of dodo, which has the slug as the subdomain.
+	slug, path, err := parseURL(u.String())
+	if err != nil {
+		if projectsErr != nil {
+			return "", "", fmt.Errorf("failed to list the projects to resolve the URL: %w", projectsErr)
+		}
+		return "", "", fmt.Errorf("the URL does not belong to any of your projects: %s", documentURL)
 	}
+	return slug, normalizeDocumentPath(path), nil
+}
 
-	data := openapi.DocumentGetResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", fmt.Errorf("failed to parse the response: %w", err)
+// parseDocumentURL parses the URL of a page. The scheme can be omitted, e.g. `localhost:8080/guide`.
+func parseDocumentURL(documentURL string) (*url.URL, error) {
+	if !strings.Contains(documentURL, "://") {
+		documentURL = "https://" + documentURL
 	}
-	if data.Markdown == nil {
-		return "", errors.New("the response does not contain markdown data")
+	u, err := url.Parse(documentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the document URL: %w", err)
 	}
-	return *data.Markdown, nil
+	if u.Host == "" {
+		return nil, fmt.Errorf("the document URL has no host: %s", documentURL)
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u, nil
 }
 
+// parseURL takes the slug from the first label of a host on the default domain of dodo, which has four labels.
 func parseURL(documentURL string) (string, string, error) {
 	u, err := url.Parse(documentURL)
 	if err != nil {
@@ -1,45 +1,19 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
-
+	"github.com/toritoritori29/dodo-cli/src/api"
 	"github.com/toritoritori29/dodo-cli/src/openapi"
 )
 
-func sendSearchRequest(env *EnvArgs, endpoint Endpoint, query string) ([]openapi.SearchRecord, error) {
+// sendSearchRequest searches the documents. If projects is not empty, only the projects with these IDs are searched.
+func sendSearchRequest(client *api.Client, query string, projects []string) ([]openapi.SearchRecord, error) {
 	body := openapi.SearchPostRequest{
-		Query: query,
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal the search request body: %w", err)
+		Query:    query,
+		Projects: projects,
 	}
-
-	req, err := http.NewRequest(http.MethodPost, endpoint.SearchURL(), strings.NewReader(string(bodyBytes)))
+	data, err := client.Search(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create a new request from the body: %w", err)
-	}
-	bearer := "Bearer " + env.BearerToken()
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", bearer)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send a request to the server: %w", err)
-	}
-	defer resp.Body.Close() //nolint:errcheck
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("the server returned non-200 status code: %d", resp.StatusCode)
-	}
-
-	data := openapi.SearchPostResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to parse the response: %w", err)
+		return nil, err //nolint:wrapcheck
 	}
 	return data.Records, nil
 }
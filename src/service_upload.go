@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+const ManifestPathSuffix = "/manifest"
+
+type ManifestResponse struct {
+	Status     string   `json:"status"`
+	Message    string   `json:"message"`
+	ManifestID string   `json:"manifest_id"`
+	Missing    []string `json:"missing"`
+}
+
+// ManifestURL returns the negotiation endpoint that belongs to the given upload endpoint.
+func ManifestURL(uploadURL string) string {
+	return strings.TrimSuffix(uploadURL, "/") + ManifestPathSuffix
+}
+
+// sendUploadManifestRequest sends the manifest to the server and returns the blobs it is missing.
+func sendUploadManifestRequest(client *api.Client, uri string, manifest *UploadManifest) (*ManifestResponse, error) {
+	data := ManifestResponse{}
+	if err := client.DoJSON(http.MethodPost, uri, manifest, &data); err != nil {
+		return nil, fmt.Errorf("failed to negotiate the manifest: %w", err)
+	}
+	return &data, nil
+}
+
+const UploadSessionPathSuffix = "/sessions"
+
+type CreateUploadSessionRequest struct {
+	Metadata   *Metadata `json:"metadata"`
+	ManifestID string    `json:"manifest_id,omitempty"`
+	Size       int64     `json:"size"`
+	ChunkSize  int64     `json:"chunk_size"`
+	Digest     string    `json:"digest"`
+}
+
+type UploadSessionResponse struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	SessionID string `json:"session_id"`
+	Received  []int  `json:"received"` // indexes of the chunks the server already has
+}
+
+// UploadSessionURL returns the endpoint of the upload sessions, or of a single session if elements are given.
+func UploadSessionURL(uploadURL string, elements ...string) string {
+	u := strings.TrimSuffix(uploadURL, "/") + UploadSessionPathSuffix
+	for _, e := range elements {
+		u += "/" + url.PathEscape(e)
+	}
+	return u
+}
+
+// createUploadSession starts a chunked upload.
+func createUploadSession(client *api.Client, uploadURL string, body *CreateUploadSessionRequest) (*UploadSessionResponse, error) {
+	data := UploadSessionResponse{}
+	if err := client.DoJSON(http.MethodPost, UploadSessionURL(uploadURL), body, &data); err != nil {
+		return nil, fmt.Errorf("failed to start the upload session: %w", err)
+	}
+	return &data, nil
+}
+
+// getUploadSession returns the chunks the server has received so far.
+func getUploadSession(client *api.Client, uploadURL, sessionID string) (*UploadSessionResponse, error) {
+	data := UploadSessionResponse{}
+	if err := client.DoJSON(http.MethodGet, UploadSessionURL(uploadURL, sessionID), nil, &data); err != nil {
+		return nil, fmt.Errorf("failed to get the upload session: %w", err)
+	}
+	return &data, nil
+}
+
+// uploadChunk sends a part of the archive. newBody is called for every attempt.
+func uploadChunk(client *api.Client, uploadURL, sessionID string, index int, offset, size, total int64, newBody func() io.Reader) error {
+	uri := UploadSessionURL(uploadURL, sessionID, "chunks", strconv.Itoa(index))
+	resp, err := client.Do(func() (*http.Request, error) {
+		req, err := client.NewRequest(http.MethodPut, uri, newBody())
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+size-1, total))
+		return req, nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if !api.IsSuccess(resp.StatusCode) {
+		return api.NewError(resp)
+	}
+	return nil
+}
+
+// completeUploadSession asks the server to assemble the chunks and publish the document.
+func completeUploadSession(client *api.Client, uploadURL, sessionID string) (*UploadResponse, error) {
+	data := UploadResponse{}
+	if err := client.DoJSON(http.MethodPost, UploadSessionURL(uploadURL, sessionID, "complete"), nil, &data); err != nil {
+		return nil, fmt.Errorf("failed to complete the upload session: %w", err)
+	}
+	return &data, nil
+}
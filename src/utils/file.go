@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"os"
+)
+
+// WriteFileKeepingMode writes data to the file, keeping its permission if it already exists.
+// A new file is created with 0644.
+func WriteFileKeepingMode(path string, data []byte) error {
+	mode := os.FileMode(0o644) //nolint:mnd
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(path, data, mode) //nolint:wrapcheck
+}
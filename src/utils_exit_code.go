@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/toritoritori29/dodo-cli/src/api"
+	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
+)
+
+// Exit codes of the process. Scripts can tell the errors returned by the server apart with them.
+// 2 is left out, as shells use it for the misuse of a command.
+const (
+	ExitCodeOK            = 0
+	ExitCodeError         = 1
+	ExitCodeUnauthorized  = 3
+	ExitCodeForbidden     = 4
+	ExitCodeNotFound      = 5
+	ExitCodeQuotaExceeded = 6
+	ExitCodeValidation    = 7
+	ExitCodeServer        = 8
+)
+
+// ExitCode returns the exit code for the error.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var herr *appErrors.HandledError
+	if errors.As(err, &herr) {
+		return herr.ExitCode
+	}
+
+	var merr *appErrors.MultiError
+	if errors.As(err, &merr) {
+		// Report the first error that has a specific exit code.
+		for _, e := range merr.Errors() {
+			if code := ExitCode(e); code != ExitCodeError {
+				return code
+			}
+		}
+		return ExitCodeError
+	}
+
+	switch {
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, ErrNotAuthenticated):
+		return ExitCodeUnauthorized
+	case errors.Is(err, api.ErrForbidden):
+		return ExitCodeForbidden
+	case errors.Is(err, api.ErrNotFound):
+		return ExitCodeNotFound
+	case errors.Is(err, api.ErrQuotaExceeded):
+		return ExitCodeQuotaExceeded
+	case errors.Is(err, api.ErrValidation):
+		return ExitCodeValidation
+	case errors.Is(err, api.ErrServer):
+		return ExitCodeServer
+	default:
+		return ExitCodeError
+	}
+}
+
+// errorHint returns what the user can do about the error, or an empty string if there is nothing to suggest.
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		return "Run `dodo login` or set DODO_API_KEY. The credentials may have expired."
+	case errors.Is(err, api.ErrForbidden):
+		return "The account or the API key has no access to the project. Check that it belongs to the organization of the project."
+	case errors.Is(err, api.ErrNotFound):
+		return "Check the project ID, the slug and the path. Check --endpoint if you changed it."
+	case errors.Is(err, api.ErrQuotaExceeded):
+		return "A limit of the plan has been reached. Wait a moment and retry, or reduce the size of the upload."
+	case errors.Is(err, api.ErrValidation):
+		return "The server rejected the request. Run `dodo check` to validate the project, and check the arguments."
+	case errors.Is(err, api.ErrServer):
+		return "The server failed to handle the request. Retry later."
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/api"
+	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
+	"github.com/toritoritori29/dodo-cli/src/openapi"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+	multi := appErrors.NewMultiError()
+	multi.Add(errors.New("first"))
+	multi.Add(fmt.Errorf("second: %w", &api.Error{StatusCode: http.StatusForbidden}))
+
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitCodeOK},
+		{errors.New("error"), ExitCodeError},
+		{&api.Error{StatusCode: http.StatusConflict}, ExitCodeError},
+		{ErrNotAuthenticated, ExitCodeUnauthorized},
+		{&api.Error{StatusCode: http.StatusUnauthorized}, ExitCodeUnauthorized},
+		{fmt.Errorf("failed to upload file: %w", &api.Error{StatusCode: http.StatusForbidden}), ExitCodeForbidden},
+		{&api.Error{StatusCode: http.StatusNotFound}, ExitCodeNotFound},
+		{&api.Error{StatusCode: http.StatusTooManyRequests}, ExitCodeQuotaExceeded},
+		{&api.Error{StatusCode: http.StatusBadRequest}, ExitCodeValidation},
+		{&api.Error{StatusCode: http.StatusBadGateway}, ExitCodeServer},
+		{&multi, ExitCodeForbidden},
+		{appErrors.NewHandledError(ExitCodeNotFound), ExitCodeNotFound},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ExitCode(test.err), "error: %v", test.err)
+	}
+}
+
+func TestHandleServerError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(openapi.DocumentGetResponseError{Status: openapi.Error, Message: "document not found"}) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewAPIClient(server.URL, EnvArgs{APIKey: "test-token"})
+	require.NoError(t, err)
+	_, err = sendReadDocumentRequest(client, "slug", "missing.md")
+	require.ErrorIs(t, err, api.ErrNotFound)
+
+	stderr := &bytes.Buffer{}
+	printer := NewErrorPrinter(NoColor)
+	printer.stderr = stderr
+	handled := printer.HandleError(err)
+	require.ErrorIs(t, handled, appErrors.ErrAlreadyHandled)
+	assert.Equal(t, ExitCodeNotFound, ExitCode(handled))
+
+	output := stderr.String()
+	assert.Contains(t, output, "the server returned status 404 (not found): document not found")
+	assert.Contains(t, output, errorHint(err))
+}
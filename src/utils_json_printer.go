@@ -55,3 +55,19 @@ func (j *JSONWriter) ShowFailedJSONText(err error) {
 	}
 	j.Write(string(jsonData))
 }
+
+func (j *JSONWriter) ShowDryRunJSONText(report *DryRunReport) {
+	data := struct {
+		Status string `json:"status"`
+		*DryRunReport
+	}{
+		Status:       "dry_run",
+		DryRunReport: report,
+	}
+	// Convert data to JSON format
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	j.Write(string(jsonData))
+}
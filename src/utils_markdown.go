@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MarkdownStyle returns the glamour style which fits the terminal.
+// Call it before a bubbletea program starts, because it queries the background color of the terminal.
+func MarkdownStyle(color bool) string {
+	if !color {
+		return styles.NoTTYStyle
+	}
+	if lipgloss.HasDarkBackground() {
+		return styles.DarkStyle
+	}
+	return styles.LightStyle
+}
+
+// RenderMarkdown renders the markdown for the terminal, wrapping the lines at width.
+func RenderMarkdown(markdown, style string, width int) (string, error) {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the markdown renderer: %w", err)
+	}
+	rendered, err := renderer.Render(markdown)
+	if err != nil {
+		return "", fmt.Errorf("failed to render the markdown: %w", err)
+	}
+	return rendered, nil
+}
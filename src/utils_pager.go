@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PagerModel shows a long text in a scrollable view with `less`-like key bindings.
+// Press `/` to find a text in the document, and `n` / `N` to jump to the next / previous match.
+type PagerModel struct {
+	viewport viewport.Model
+	find     textinput.Model
+	finding  bool
+
+	lines   []string // lines of the content as is
+	plain   []string // lines of the content without escape sequences, to search the text
+	query   string
+	matches []int // indices of the lines which contain the query
+	match   int   // index of the current match in matches
+
+	statusStyle lipgloss.Style
+	matchStyle  lipgloss.Style
+}
+
+func NewPagerModel(width, height int) PagerModel {
+	find := textinput.New()
+	find.Prompt = "/"
+	find.CharLimit = 255
+
+	vp := viewport.New(width, max(height-1, 1))
+	vp.MouseWheelEnabled = true
+	return PagerModel{
+		viewport:    vp,
+		find:        find,
+		statusStyle: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: TextColorLightDim, Dark: TextColorDarkDim}),
+		matchStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(TextColorLight0)).
+			Background(lipgloss.AdaptiveColor{Light: PrimaryColorLight, Dark: PrimaryColorDark}),
+	}
+}
+
+// SetContent replaces the text and scrolls back to the top. The query of the previous text is kept.
+func (m *PagerModel) SetContent(content string) {
+	m.lines = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	m.plain = make([]string, len(m.lines))
+	for i, line := range m.lines {
+		m.plain[i] = strings.ToLower(ansi.Strip(line))
+	}
+	m.viewport.GotoTop()
+	m.search()
+}
+
+// SetSize sets the size of the pager, including the status line.
+func (m *PagerModel) SetSize(width, height int) {
+	m.viewport.Width = width
+	m.viewport.Height = max(height-1, 1)
+	m.find.Width = width
+	m.refresh()
+}
+
+// Finding reports whether the find prompt has the focus. The keys should not be handled by the parent model while it does.
+func (m PagerModel) Finding() bool {
+	return m.finding
+}
+
+// Matches returns the line numbers, starting from 0, of the lines which contain the query.
+func (m PagerModel) Matches() []int {
+	return m.matches
+}
+
+func (m PagerModel) Update(msg tea.Msg) (PagerModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	if m.finding {
+		switch keyMsg.Type { //nolint:exhaustive
+		case tea.KeyEnter:
+			m.finding = false
+			m.find.Blur()
+			m.query = m.find.Value()
+			m.search()
+			return m, nil
+		case tea.KeyEscape:
+			m.finding = false
+			m.find.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.find, cmd = m.find.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "/":
+		m.finding = true
+		m.find.SetValue("")
+		return m, m.find.Focus()
+	case "n":
+		m.jump(m.match + 1)
+		return m, nil
+	case "N":
+		m.jump(m.match - 1)
+		return m, nil
+	case "g", "home":
+		m.viewport.GotoTop()
+		return m, nil
+	case "G", "end":
+		m.viewport.GotoBottom()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// search finds the lines which contain the query and jumps to the first one. The search is case-insensitive.
+func (m *PagerModel) search() {
+	m.matches = nil
+	m.match = 0
+	if m.query != "" {
+		query := strings.ToLower(m.query)
+		for i, line := range m.plain {
+			if strings.Contains(line, query) {
+				m.matches = append(m.matches, i)
+			}
+		}
+	}
+	m.jump(0)
+}
+
+// jump scrolls to the i-th match. The index wraps around, so that `n` on the last match goes back to the first one.
+func (m *PagerModel) jump(i int) {
+	if len(m.matches) > 0 {
+		m.match = (i%len(m.matches) + len(m.matches)) % len(m.matches)
+		m.viewport.SetYOffset(m.matches[m.match])
+	}
+	m.refresh()
+}
+
+// refresh sets the content of the viewport, highlighting the line of the current match.
+func (m *PagerModel) refresh() {
+	lines := m.lines
+	if len(m.matches) > 0 {
+		lines = make([]string, len(m.lines))
+		copy(lines, m.lines)
+		line := m.matches[m.match]
+		lines[line] = m.matchStyle.Render(ansi.Strip(m.lines[line]))
+	}
+	offset := m.viewport.YOffset
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+	m.viewport.SetYOffset(offset)
+}
+
+func (m PagerModel) View() string {
+	return m.viewport.View() + "\n" + m.statusLine()
+}
+
+func (m PagerModel) statusLine() string {
+	if m.finding {
+		return m.find.View()
+	}
+	status := fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100)
+	switch {
+	case m.query != "" && len(m.matches) == 0:
+		status = fmt.Sprintf("%s  no match for %q", status, m.query)
+	case m.query != "":
+		status = fmt.Sprintf("%s  match %d/%d for %q (n/N to move)", status, m.match+1, len(m.matches), m.query)
+	}
+	return m.statusStyle.Render(ansi.Truncate(status, m.viewport.Width, "..."))
+}
+
+// pagerProgram shows a PagerModel in the whole screen, with a title at the top.
+type pagerProgram struct {
+	pager PagerModel
+	title string
+}
+
+// RunPager shows the content in the alternate screen until the user quits with q, Esc or Ctrl-C.
+func RunPager(title, content string) error {
+	pager := NewPagerModel(MaxWidth, 1)
+	pager.SetContent(content)
+	p := tea.NewProgram(pagerProgram{pager: pager, title: title}, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to run the pager: %w", err)
+	}
+	return nil
+}
+
+func (m pagerProgram) Init() tea.Cmd {
+	return nil
+}
+
+func (m pagerProgram) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.pager.SetSize(msg.Width, msg.Height-1)
+		return m, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if !m.pager.Finding() && (msg.String() == "q" || msg.Type == tea.KeyEscape) {
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.pager, cmd = m.pager.Update(msg)
+	return m, cmd
+}
+
+func (m pagerProgram) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: PrimaryColorLight, Dark: PrimaryColorDark}).
+		Bold(true)
+	return titleStyle.Render(ansi.Truncate(m.title, m.pager.viewport.Width, "...")) + "\n" + m.pager.View()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func typeKeys(t *testing.T, m PagerModel, keys ...string) PagerModel {
+	t.Helper()
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEscape}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestPagerFind(t *testing.T) {
+	t.Parallel()
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	lines[10] = "\x1b[1mFirst Match\x1b[0m"
+	lines[30] = "second match"
+
+	m := NewPagerModel(40, 11)
+	m.SetContent(strings.Join(lines, "\n"))
+	assert.Equal(t, 10, m.viewport.Height, "a line is used by the status")
+
+	m = typeKeys(t, m, "/")
+	require.True(t, m.Finding())
+	m = typeKeys(t, m, "m", "a", "t", "c", "h", "enter")
+	require.False(t, m.Finding())
+	assert.Equal(t, []int{10, 30}, m.Matches(), "the search should ignore the case and the escape sequences")
+	assert.Equal(t, 10, m.viewport.YOffset)
+	assert.Contains(t, m.View(), "match 1/2")
+
+	m = typeKeys(t, m, "n")
+	assert.Equal(t, 30, m.viewport.YOffset)
+	m = typeKeys(t, m, "n")
+	assert.Equal(t, 10, m.viewport.YOffset, "n should wrap around")
+	m = typeKeys(t, m, "N")
+	assert.Equal(t, 30, m.viewport.YOffset)
+
+	// Escape cancels the prompt and keeps the previous query.
+	m = typeKeys(t, m, "/", "x", "esc")
+	assert.False(t, m.Finding())
+	assert.Equal(t, []int{10, 30}, m.Matches())
+
+	m = typeKeys(t, m, "/", "n", "o", "n", "e", "enter")
+	assert.Empty(t, m.Matches())
+	assert.Contains(t, m.View(), `no match for "none"`)
+
+	m = typeKeys(t, m, "G")
+	assert.True(t, m.viewport.AtBottom())
+	m = typeKeys(t, m, "g")
+	assert.True(t, m.viewport.AtTop())
+}
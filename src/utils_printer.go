@@ -83,16 +83,16 @@ func (p *ErrorPrinter) HandleError(err error) error {
 		for _, e := range merr.Errors() {
 			p.HandleError(e) //nolint: errcheck
 		}
-		return appErrors.ErrAlreadyHandled
+		return appErrors.NewHandledError(ExitCode(merr))
 	}
 
 	var perr *appErrors.ParseError
 	if errors.As(err, &perr) {
 		p.printParseError(perr)
-		return appErrors.ErrAlreadyHandled
+		return appErrors.NewHandledError(ExitCodeError)
 	}
 	p.printError(err)
-	return appErrors.ErrAlreadyHandled
+	return appErrors.NewHandledError(ExitCode(err))
 }
 
 func (p *ErrorPrinter) printParseError(err *appErrors.ParseError) {
@@ -119,4 +119,9 @@ func (p *ErrorPrinter) printError(err error) {
 	listIcon := p.style.Primary.Render(fmt.Sprintf("%*s", p.padding, "⨯"))
 	message := p.style.Primary.Render(err.Error())
 	_, _ = fmt.Fprintf(p.stderr, "%s %s\n", listIcon, message)
+
+	if hint := errorHint(err); hint != "" {
+		arrow := p.style.Secondary.Render(fmt.Sprintf("%*s", p.padding+2, ">"))
+		_, _ = fmt.Fprintf(p.stderr, "%s %s\n", arrow, hint)
+	}
 }
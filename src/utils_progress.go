@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caarlos0/log"
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	ProgressBarInterval = 100 * time.Millisecond
+	ProgressLogInterval = 2 * time.Second
+)
+
+// ProgressEvent describes how much of a transfer has been done so far.
+type ProgressEvent struct {
+	Sent    int64
+	Total   int64
+	Elapsed time.Duration
+}
+
+// Rate returns the transfer rate in bytes per second.
+func (e ProgressEvent) Rate() float64 {
+	if e.Elapsed <= 0 {
+		return 0
+	}
+	return float64(e.Sent) / e.Elapsed.Seconds()
+}
+
+func (e ProgressEvent) Percent() float64 {
+	if e.Total <= 0 {
+		return 0
+	}
+	return min(float64(e.Sent)/float64(e.Total), 1)
+}
+
+func (e ProgressEvent) String() string {
+	return fmt.Sprintf("%s / %s (%.0f%%) at %s/s",
+		formatBytes(e.Sent), formatBytes(e.Total), e.Percent()*100, formatBytes(int64(e.Rate())))
+}
+
+// ProgressReporter receives progress events while a transfer is running.
+// Report is called periodically and Finish is called once at the end.
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+	Finish(event ProgressEvent)
+}
+
+// NewUploadProgressReporter selects the reporter for the output format.
+// A progress bar is shown only when stderr is a terminal, otherwise (e.g. in CI) log lines are printed.
+func NewUploadProgressReporter(args UploadArgs) ProgressReporter {
+	if args.format == FormatJSON {
+		return &jsonProgressReporter{writer: NewJSONWriterFromArgs(args), throttle: throttle{interval: ProgressLogInterval}}
+	}
+	if term.IsTerminal(os.Stderr.Fd()) && !args.debug {
+		return newBarProgressReporter()
+	}
+	return &logProgressReporter{throttle: throttle{interval: ProgressLogInterval}}
+}
+
+// progressReader counts the bytes read through it and forwards them to the reporter.
+// It is read from the goroutine writing the request body, so the counter is atomic.
+type progressReader struct {
+	reader   io.Reader
+	reporter ProgressReporter
+	total    int64
+	sent     atomic.Int64
+	started  time.Time
+	once     sync.Once
+}
+
+func newProgressReader(reader io.Reader, total int64, reporter ProgressReporter) *progressReader {
+	return &progressReader{
+		reader:   reader,
+		reporter: reporter,
+		total:    total,
+		started:  time.Now(),
+	}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent.Add(int64(n))
+	// EOF does not finish the transfer, because a retry reads the body again with a new reader.
+	if !errors.Is(err, io.EOF) {
+		r.reporter.Report(r.event())
+	}
+	return n, err //nolint:wrapcheck
+}
+
+// Finish reports the final state once. The caller calls it after the last attempt, whether it succeeded or not,
+// so that the progress bar does not keep the terminal.
+func (r *progressReader) Finish() {
+	r.once.Do(func() {
+		r.reporter.Finish(r.event())
+	})
+}
+
+func (r *progressReader) event() ProgressEvent {
+	return ProgressEvent{
+		Sent:    r.sent.Load(),
+		Total:   r.total,
+		Elapsed: time.Since(r.started),
+	}
+}
+
+// throttle reports whether enough time has passed since the last report.
+type throttle struct {
+	interval time.Duration
+	last     time.Time
+}
+
+func (t *throttle) allow() bool {
+	now := time.Now()
+	if now.Sub(t.last) < t.interval {
+		return false
+	}
+	t.last = now
+	return true
+}
+
+type logProgressReporter struct {
+	throttle throttle
+}
+
+func (r *logProgressReporter) Report(event ProgressEvent) {
+	if r.throttle.allow() {
+		log.Infof("uploading %s", event)
+	}
+}
+
+func (r *logProgressReporter) Finish(event ProgressEvent) {
+	log.Infof("uploaded %s", event)
+}
+
+type jsonProgressFormat struct {
+	Status       string  `json:"status"`
+	BytesSent    int64   `json:"bytes_sent"`
+	BytesTotal   int64   `json:"bytes_total"`
+	BytesPerSec  float64 `json:"bytes_per_sec"`
+	ElapsedMilli int64   `json:"elapsed_ms"`
+}
+
+type jsonProgressReporter struct {
+	writer   *JSONWriter
+	throttle throttle
+}
+
+func (r *jsonProgressReporter) Report(event ProgressEvent) {
+	if r.throttle.allow() {
+		r.write(event)
+	}
+}
+
+func (r *jsonProgressReporter) Finish(event ProgressEvent) {
+	r.write(event)
+}
+
+func (r *jsonProgressReporter) write(event ProgressEvent) {
+	data, err := json.Marshal(jsonProgressFormat{
+		Status:       "progress",
+		BytesSent:    event.Sent,
+		BytesTotal:   event.Total,
+		BytesPerSec:  event.Rate(),
+		ElapsedMilli: event.Elapsed.Milliseconds(),
+	})
+	if err != nil {
+		return
+	}
+	r.writer.Write(string(data))
+}
+
+// barProgressReporter renders a progress bar with bubbletea on stderr.
+// The program is started on the first event, so that nothing is drawn if the transfer never begins.
+type barProgressReporter struct {
+	program  *tea.Program
+	throttle throttle
+	start    sync.Once
+	done     chan struct{}
+}
+
+func newBarProgressReporter() *barProgressReporter {
+	return &barProgressReporter{
+		program: tea.NewProgram(
+			newProgressModel(),
+			tea.WithOutput(os.Stderr),
+			tea.WithInput(nil),
+			tea.WithoutSignalHandler(),
+		),
+		throttle: throttle{interval: ProgressBarInterval},
+		done:     make(chan struct{}),
+	}
+}
+
+func (r *barProgressReporter) run() {
+	r.start.Do(func() {
+		go func() {
+			defer close(r.done)
+			if _, err := r.program.Run(); err != nil {
+				log.Debugf("failed to render the progress bar: %v", err)
+			}
+		}()
+	})
+}
+
+func (r *barProgressReporter) Report(event ProgressEvent) {
+	r.run()
+	if r.throttle.allow() {
+		r.program.Send(progressMsg(event))
+	}
+}
+
+func (r *barProgressReporter) Finish(event ProgressEvent) {
+	r.run()
+	r.program.Send(progressDoneMsg(event))
+	<-r.done
+}
+
+type (
+	progressMsg     ProgressEvent
+	progressDoneMsg ProgressEvent
+)
+
+type progressModel struct {
+	bar   progress.Model
+	event ProgressEvent
+	info  lipgloss.Style
+}
+
+func newProgressModel() progressModel {
+	return progressModel{
+		bar:  progress.New(progress.WithDefaultGradient(), progress.WithWidth(40)),
+		info: lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+	}
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case progressMsg:
+		m.event = ProgressEvent(msg)
+	case progressDoneMsg:
+		m.event = ProgressEvent(msg)
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m progressModel) View() string {
+	return m.bar.ViewAs(m.event.Percent()) + " " + m.info.Render(m.event.String()) + "\n"
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
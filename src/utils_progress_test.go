@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toritoritori29/dodo-cli/src/api"
+)
+
+type recordingReporter struct {
+	mu       sync.Mutex
+	reports  []ProgressEvent
+	finishes []ProgressEvent
+}
+
+func (r *recordingReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, event)
+}
+
+func (r *recordingReporter) Finish(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finishes = append(r.finishes, event)
+}
+
+func TestProgressReader(t *testing.T) {
+	t.Parallel()
+	data := bytes.Repeat([]byte("a"), 1000)
+	reporter := &recordingReporter{}
+	reader := newProgressReader(iotest.OneByteReader(bytes.NewReader(data)), int64(len(data)), reporter)
+
+	read, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+	assert.Len(t, reporter.reports, 1000)
+	assert.Equal(t, int64(500), reporter.reports[499].Sent)
+
+	// EOF does not finish the transfer. Finish is reported once even if it is called twice.
+	assert.Empty(t, reporter.finishes)
+	reader.Finish()
+	reader.Finish()
+	require.Len(t, reporter.finishes, 1)
+	assert.Equal(t, int64(1000), reporter.finishes[0].Sent)
+	assert.InDelta(t, 1.0, reporter.finishes[0].Percent(), 0.0001)
+}
+
+func TestArchiveUploadReportsProgress(t *testing.T) {
+	prepareUploadProject(t)
+	server := newFakeServer(t)
+	file, err := os.Open(".dodo.yaml")
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck
+	metadata, err := parseConfigFileToMetadata(file, ".dodo.yaml")
+	require.NoError(t, err)
+
+	archive, err := NewArchive("")
+	require.NoError(t, err)
+	defer archive.Close() //nolint:errcheck
+	require.Nil(t, archive.Archive(metadata))
+	info, err := archive.File.Stat()
+	require.NoError(t, err)
+
+	reporter := &recordingReporter{}
+	client, err := NewAPIClient(server.UploadURL(), EnvArgs{APIKey: "test-token"}, api.WithRetry(api.NewRetryPolicy(0)))
+	require.NoError(t, err)
+	_, err = archive.Upload(client, server.UploadURL(), reporter)
+	require.NoError(t, err)
+	require.Len(t, reporter.finishes, 1)
+	assert.Equal(t, info.Size(), reporter.finishes[0].Sent)
+	assert.Equal(t, info.Size(), reporter.finishes[0].Total)
+	assert.Len(t, server.Received(0), 2)
+
+	// A retried upload shows the progress of every attempt and finishes once.
+	server.InjectFailures("POST "+fakeUploadPath, fakeFailure{status: http.StatusServiceUnavailable})
+	reporter = &recordingReporter{}
+	client, err = NewAPIClient(server.UploadURL(), EnvArgs{APIKey: "test-token"}, api.WithRetry(api.RetryPolicy{MaxRetries: 1, Sleep: func(time.Duration) {}}))
+	require.NoError(t, err)
+	_, err = archive.Upload(client, server.UploadURL(), reporter)
+	require.NoError(t, err)
+	assert.Equal(t, 3, server.Requests("POST "+fakeUploadPath), "the second upload should be retried once")
+	require.Len(t, reporter.finishes, 1)
+	assert.Equal(t, info.Size(), reporter.finishes[0].Sent)
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "3.0 MiB", formatBytes(3*1024*1024))
+}
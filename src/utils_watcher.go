@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/caarlos0/log"
+	"github.com/mattn/go-zglob"
+	"github.com/toritoritori29/dodo-cli/src/config"
+)
+
+const (
+	WatchPollInterval = 500 * time.Millisecond
+	WatchDebounce     = 300 * time.Millisecond
+)
+
+// WatchTarget describes the files that affect the build result of a project.
+// Patterns are expanded on every snapshot so that newly created files are picked up.
+type WatchTarget struct {
+	Files    []string
+	Patterns []string
+}
+
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+// NewWatchTargetFromConfig parses the config file and collects the config itself,
+// every markdown file in the page tree, the `match` patterns and the asset patterns.
+func NewWatchTargetFromConfig(configPath string) (WatchTarget, error) {
+	configFile, err := os.Open(configPath)
+	if err != nil {
+		return WatchTarget{}, fmt.Errorf("failed to open the config file: %w", err)
+	}
+	defer configFile.Close() //nolint:errcheck
+
+	version, err := config.DetectConfigVersion(configFile)
+	if err != nil {
+		return WatchTarget{}, fmt.Errorf("failed to detect the config version: %w", err)
+	}
+	if _, err := configFile.Seek(0, 0); err != nil {
+		return WatchTarget{}, fmt.Errorf("failed to reset file pointer: %w", err)
+	}
+
+	target := WatchTarget{Files: []string{configPath}}
+	switch version {
+	case 1:
+		state := config.NewParseStateV1(configPath, "./")
+		conf, err := config.ParseConfigV1(state, configFile)
+		if err != nil {
+			return WatchTarget{}, fmt.Errorf("failed to parse the config file: %w", err)
+		}
+		target.Files = appendConfigPageFilesV1(target.Files, conf.Pages)
+		target.Patterns = append(target.Patterns, conf.MatchPatterns...)
+		for _, a := range conf.Assets {
+			target.Patterns = append(target.Patterns, filepath.Clean(string(a)))
+		}
+		if conf.Project.Logo != "" {
+			target.Files = append(target.Files, conf.Project.Logo)
+		}
+	case 2:
+		state := config.NewParseStateV2(configPath, "./")
+		conf, err := config.ParseConfigV2(state, configFile)
+		if err != nil {
+			return WatchTarget{}, fmt.Errorf("failed to parse the config file: %w", err)
+		}
+		target.Files = appendConfigPageFilesV2(target.Files, conf.Pages)
+		target.Files = append(target.Files, conf.IncludedFiles...)
+		target.Patterns = append(target.Patterns, conf.MatchPatterns...)
+		for _, a := range conf.Assets {
+			target.Patterns = append(target.Patterns, filepath.Clean(string(a)))
+		}
+		if conf.Project.Logo != "" {
+			target.Files = append(target.Files, conf.Project.Logo)
+		}
+	default:
+		return WatchTarget{}, fmt.Errorf("unsupported config version: %d", version)
+	}
+	return target, nil
+}
+
+func appendConfigPageFilesV1(files []string, pages []config.ConfigPageV1) []string {
+	for _, p := range pages {
+		if p.Markdown != "" {
+			files = append(files, p.Markdown)
+		}
+		files = appendConfigPageFilesV1(files, p.Children)
+	}
+	return files
+}
+
+func appendConfigPageFilesV2(files []string, pages []config.ConfigPageV2) []string {
+	for _, p := range pages {
+		for _, lp := range p.LangPage {
+			files = append(files, lp.Filepath)
+		}
+		files = appendConfigPageFilesV2(files, p.Children)
+	}
+	return files
+}
+
+// Snapshot returns the modification time and size of every watched file.
+// Files that do not exist are omitted, so deleting a file is reported as a change.
+func (t WatchTarget) Snapshot() map[string]fileStamp {
+	paths := make([]string, 0, len(t.Files))
+	paths = append(paths, t.Files...)
+	for _, pattern := range t.Patterns {
+		matches, err := zglob.Glob(pattern)
+		if err != nil {
+			log.Debugf("failed to expand the pattern %s: %v", pattern, err)
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+
+	snapshot := make(map[string]fileStamp, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		snapshot[filepath.Clean(p)] = fileStamp{
+			modTime: info.ModTime().UnixNano(),
+			size:    info.Size(),
+		}
+	}
+	return snapshot
+}
+
+// Watcher polls the files of a project and runs a callback when they change.
+type Watcher struct {
+	configPath string
+	interval   time.Duration
+	debounce   time.Duration
+	target     WatchTarget
+}
+
+func NewWatcher(configPath string) *Watcher {
+	return &Watcher{
+		configPath: configPath,
+		interval:   WatchPollInterval,
+		debounce:   WatchDebounce,
+		target:     WatchTarget{Files: []string{configPath}},
+	}
+}
+
+// Run blocks until ctx is cancelled.
+// onChange is called once the watched files have stopped changing for the debounce period.
+// The watch target is rebuilt from the config file after every call, so that edits to the
+// config itself (new pages, new patterns) are reflected.
+func (w *Watcher) Run(ctx context.Context, onChange func()) error {
+	w.reloadTarget()
+	prev := w.target.Snapshot()
+	log.Infof("watching %d files for changes. press Ctrl-C to stop", len(prev))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pending := false
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current := w.target.Snapshot()
+		if !maps.Equal(prev, current) {
+			log.Debugf("change detected in the watched files")
+			prev = current
+			pending = true
+			lastChange = time.Now()
+			continue
+		}
+		if !pending || time.Since(lastChange) < w.debounce {
+			continue
+		}
+
+		// Take the baseline before the run, so that the files saved during the run trigger the next one.
+		// The target is reloaded first, because the change may have added files to the config.
+		pending = false
+		w.reloadTarget()
+		prev = w.target.Snapshot()
+		onChange()
+	}
+}
+
+func (w *Watcher) reloadTarget() {
+	target, err := NewWatchTargetFromConfig(w.configPath)
+	if err != nil {
+		// Keep watching the previous files so that fixing the config triggers a rebuild.
+		log.Debugf("failed to collect the watch target, keep the previous one: %v", err)
+		return
+	}
+	w.target = target
+}
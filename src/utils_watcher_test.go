@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchTargetSnapshot(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, "README.md", "readme")
+	prepareSubDir(t, dir, "docs")
+	prepareFile(t, dir, "docs/page1.md", "page1")
+
+	target := WatchTarget{
+		Files:    []string{filepath.Join(dir, "README.md")},
+		Patterns: []string{filepath.Join(dir, "docs", "*.md")},
+	}
+	before := target.Snapshot()
+	assert.Len(t, before, 2)
+
+	// A new file matching the pattern should be picked up.
+	prepareFile(t, dir, "docs/page2.md", "page2")
+	after := target.Snapshot()
+	assert.Len(t, after, 3)
+	assert.Contains(t, after, filepath.Join(dir, "docs", "page2.md"))
+
+	// A deleted file should disappear from the snapshot.
+	require.NoError(t, os.Remove(filepath.Join(dir, "README.md")))
+	assert.Len(t, target.Snapshot(), 2)
+}
+
+func TestNewWatchTargetFromConfig(t *testing.T) {
+	testDir := filepath.Join("config", "test_cases", "v2", "2_valid_multi_language")
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(testDir))
+	defer os.Chdir(oldWd)
+
+	target, err := NewWatchTargetFromConfig(".dodo.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, target.Files, ".dodo.yaml")
+	assert.Contains(t, target.Files, "file1.en.md")
+	assert.Contains(t, target.Files, "file2.ja.md")
+	require.Len(t, target.Patterns, 1, "the match pattern should be kept for re-expansion")
+	assert.Equal(t, "*.md", filepath.Base(target.Patterns[0]))
+}
+
+func TestWatcherRunDebounce(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, "README.md", "readme")
+
+	watcher := &Watcher{
+		configPath: filepath.Join(dir, "missing.yaml"),
+		interval:   10 * time.Millisecond,
+		debounce:   50 * time.Millisecond,
+		target:     WatchTarget{Patterns: []string{filepath.Join(dir, "*.md")}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watcher.Run(ctx, func() { calls.Add(1) }) //nolint:errcheck
+	}()
+
+	// Several changes in a short period should be coalesced into one run.
+	time.Sleep(30 * time.Millisecond)
+	prepareFile(t, dir, "page1.md", "1")
+	prepareFile(t, dir, "page2.md", "2")
+	prepareFile(t, dir, "page3.md", "3")
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+
+	cancel()
+	<-done
+}
+
+func TestWatcherRunChangeDuringRun(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, "README.md", "readme")
+
+	watcher := &Watcher{
+		configPath: filepath.Join(dir, "missing.yaml"),
+		interval:   10 * time.Millisecond,
+		debounce:   20 * time.Millisecond,
+		target:     WatchTarget{Patterns: []string{filepath.Join(dir, "*.md")}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watcher.Run(ctx, func() { //nolint:errcheck
+			// A file saved while the first run is in progress should trigger another run.
+			if calls.Add(1) == 1 {
+				os.WriteFile(filepath.Join(dir, "during.md"), []byte("saved during the run"), 0o600) //nolint:errcheck
+				time.Sleep(50 * time.Millisecond)
+			}
+		})
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	prepareFile(t, dir, "page1.md", "1")
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
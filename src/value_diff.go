@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	PageDiffAdded    = "added"
+	PageDiffRemoved  = "removed"
+	PageDiffMoved    = "moved"
+	PageDiffModified = "modified"
+)
+
+// diffContextLines is the number of unchanged lines shown around a change in a unified diff.
+const diffContextLines = 3
+
+// ProjectDiff lists the changes that an upload of the local project makes to the deployed one.
+type ProjectDiff struct {
+	ProjectID string     `json:"project_id"`
+	Pages     []PageDiff `json:"pages"`
+}
+
+// PageDiff is a change to a page in one language. Pages are identified by their language and path.
+// A page which is both moved and modified has two entries.
+type PageDiff struct {
+	Status      string `json:"status"`
+	Language    string `json:"language"`
+	Path        string `json:"path"`
+	Title       string `json:"title"`
+	OldTitle    string `json:"old_title,omitempty"`
+	Location    string `json:"location"`
+	OldLocation string `json:"old_location,omitempty"`
+	Filepath    string `json:"filepath,omitempty"`
+	Diff        string `json:"diff,omitempty"`
+}
+
+// FetchMarkdownFunc returns the markdown of the deployed page at the path.
+type FetchMarkdownFunc func(path string) (string, error)
+
+// diffEntry is a page in one language, flattened out of the page tree.
+type diffEntry struct {
+	language string
+	title    string
+	path     string
+	filepath string
+	hash     string
+	location string // the titles of the ancestors
+}
+
+func (e diffEntry) key() string {
+	return e.language + ":" + e.path
+}
+
+// NewProjectDiff compares the page trees. The markdown of a page is fetched only if the hashes differ.
+func NewProjectDiff(projectID string, local, deployed *Page, fetch FetchMarkdownFunc) (*ProjectDiff, error) {
+	localEntries := flattenDiffEntries(local)
+	deployedEntries := flattenDiffEntries(deployed)
+	deployedByKey := make(map[string]diffEntry, len(deployedEntries))
+	for _, e := range deployedEntries {
+		deployedByKey[e.key()] = e
+	}
+
+	diff := &ProjectDiff{ProjectID: projectID, Pages: []PageDiff{}}
+	localKeys := make(map[string]struct{}, len(localEntries))
+	for _, l := range localEntries {
+		localKeys[l.key()] = struct{}{}
+		d, ok := deployedByKey[l.key()]
+		if !ok {
+			diff.Pages = append(diff.Pages, PageDiff{
+				Status:   PageDiffAdded,
+				Language: l.language,
+				Path:     l.path,
+				Title:    l.title,
+				Location: l.location,
+				Filepath: l.filepath,
+			})
+			continue
+		}
+
+		if l.location != d.location {
+			diff.Pages = append(diff.Pages, PageDiff{
+				Status:      PageDiffMoved,
+				Language:    l.language,
+				Path:        l.path,
+				Title:       l.title,
+				Location:    l.location,
+				OldLocation: d.location,
+				Filepath:    l.filepath,
+			})
+		}
+
+		modified, err := diffPageContents(l, d, fetch)
+		if err != nil {
+			return nil, err
+		}
+		if modified != nil {
+			diff.Pages = append(diff.Pages, *modified)
+		}
+	}
+
+	for _, d := range deployedEntries {
+		if _, ok := localKeys[d.key()]; ok {
+			continue
+		}
+		diff.Pages = append(diff.Pages, PageDiff{
+			Status:   PageDiffRemoved,
+			Language: d.language,
+			Path:     d.path,
+			Title:    d.title,
+			Location: d.location,
+		})
+	}
+	return diff, nil
+}
+
+// diffPageContents compares the title and the markdown of a page. It returns nil if they are the same.
+func diffPageContents(local, deployed diffEntry, fetch FetchMarkdownFunc) (*PageDiff, error) {
+	page := PageDiff{
+		Status:   PageDiffModified,
+		Language: local.language,
+		Path:     local.path,
+		Title:    local.title,
+		Location: local.location,
+		Filepath: local.filepath,
+	}
+	if local.title != deployed.title {
+		page.OldTitle = deployed.title
+	}
+
+	if local.filepath != "" && (deployed.hash == "" || local.hash != deployed.hash) {
+		after, err := os.ReadFile(local.filepath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", local.filepath, err)
+		}
+		before, err := fetch(deployed.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the deployed page %s: %w", deployed.path, err)
+		}
+		page.Diff, err = unifiedDiff(before, string(after), "deployed:"+deployed.path, local.filepath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if page.OldTitle == "" && page.Diff == "" {
+		return nil, nil //nolint:nilnil
+	}
+	return &page, nil
+}
+
+// unifiedDiff returns an empty string if the texts are the same.
+func unifiedDiff(before, after, fromFile, toFile string) (string, error) {
+	if before == after {
+		return "", nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute the diff: %w", err)
+	}
+	return diff, nil
+}
+
+// flattenDiffEntries lists the pages which have a path, in the order of the tree.
+func flattenDiffEntries(root *Page) []diffEntry {
+	entries := []diffEntry{}
+	var walk func(p *Page, ancestors []*Page)
+	walk = func(p *Page, ancestors []*Page) {
+		for _, l := range p.Language {
+			if l.Path == "" {
+				continue
+			}
+			entries = append(entries, diffEntry{
+				language: l.Language,
+				title:    l.Title,
+				path:     l.Path,
+				filepath: l.Filepath,
+				hash:     l.Hash,
+				location: diffLocation(ancestors, l.Language),
+			})
+		}
+		for i := range p.Children {
+			walk(&p.Children[i], append(ancestors, p))
+		}
+	}
+	for i := range root.Children {
+		walk(&root.Children[i], nil)
+	}
+	return entries
+}
+
+// diffLocation joins the titles of the ancestors in the language, or in their first language if it is missing.
+func diffLocation(ancestors []*Page, language string) string {
+	titles := make([]string, 0, len(ancestors))
+	for _, a := range ancestors {
+		if len(a.Language) == 0 {
+			continue
+		}
+		title := a.Language[0].Title
+		for _, l := range a.Language {
+			if l.Language == language {
+				title = l.Title
+				break
+			}
+		}
+		titles = append(titles, title)
+	}
+	return strings.Join(titles, " > ")
+}
+
+// Count returns the number of changes with the status.
+func (d *ProjectDiff) Count(status string) int {
+	count := 0
+	for _, p := range d.Pages {
+		if p.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (d *ProjectDiff) HasChanges() bool {
+	return len(d.Pages) > 0
+}
+
+// Text renders the changes grouped by their status, followed by the diffs of the markdown.
+func (d *ProjectDiff) Text() string {
+	if !d.HasChanges() {
+		return fmt.Sprintf("No changes. The deployed project %s is up to date.\n", d.ProjectID)
+	}
+
+	var b strings.Builder
+	groups := []struct {
+		status string
+		label  string
+		mark   string
+	}{
+		{PageDiffAdded, "Added", "+"},
+		{PageDiffRemoved, "Removed", "-"},
+		{PageDiffMoved, "Moved", "~"},
+		{PageDiffModified, "Modified", "*"},
+	}
+	for _, g := range groups {
+		count := d.Count(g.status)
+		if count == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", g.label, count)
+		for _, p := range d.Pages {
+			if p.Status != g.status {
+				continue
+			}
+			fmt.Fprintf(&b, "  %s [%s] %s  %s%s\n", g.mark, p.Language, p.Path, p.Title, pageDiffDetail(p))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, p := range d.Pages {
+		if p.Diff != "" {
+			b.WriteString(p.Diff)
+			if !strings.HasSuffix(p.Diff, "\n") {
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "%d added, %d removed, %d moved, %d modified\n",
+		d.Count(PageDiffAdded), d.Count(PageDiffRemoved), d.Count(PageDiffMoved), d.Count(PageDiffModified))
+	return b.String()
+}
+
+func pageDiffDetail(p PageDiff) string {
+	switch p.Status {
+	case PageDiffAdded:
+		return fmt.Sprintf(" (%s)", p.Filepath)
+	case PageDiffMoved:
+		return fmt.Sprintf(" (%s -> %s)", diffLocationLabel(p.OldLocation), diffLocationLabel(p.Location))
+	case PageDiffModified:
+		if p.OldTitle != "" {
+			return fmt.Sprintf(" (title: %s -> %s)", p.OldTitle, p.Title)
+		}
+	}
+	return ""
+}
+
+func diffLocationLabel(location string) string {
+	if location == "" {
+		return "(top)"
+	}
+	return location
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/caarlos0/log"
 )
 
+var ErrNotAuthenticated = errors.New("not authenticated. Please run 'dodo login' or set the DODO_API_KEY environment variable")
+
 type EnvArgs struct {
 	APIKey      string // from DODO_API_KEY env var
 	AccessToken string // from OAuth2 login (keyring)
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/tree"
+)
+
+// Inspection describes what is shipped for a project: the metadata and the size of every blob.
+type Inspection struct {
+	Source       string           `json:"source"`
+	Project      MetadataProject  `json:"project"`
+	Page         Page             `json:"page"`
+	Assets       []InspectedAsset `json:"assets"`
+	MetadataSize int64            `json:"metadata_size"`
+	PayloadSize  int64            `json:"payload_size"`
+}
+
+type InspectedAsset struct {
+	Path     string `json:"path"`
+	Hash     string `json:"hash"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+// BlobSizeFunc returns the size of the blob in bytes.
+type BlobSizeFunc func(blob MetadataBlob) (int64, error)
+
+// NewInspection collects the sizes of the blobs referenced by the metadata.
+// The payload size is the size of metadata.json plus every distinct blob.
+func NewInspection(source string, metadata *Metadata, blobSize BlobSizeFunc) (*Inspection, error) {
+	serialized, err := metadata.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64)
+	payload := int64(len(serialized))
+	for _, blob := range metadata.ListBlobs() {
+		size, err := blobSize(blob)
+		if err != nil {
+			return nil, err
+		}
+		sizes[blob.Hash] = size
+		payload += size
+	}
+
+	assets := make([]InspectedAsset, 0, len(metadata.Asset))
+	for _, asset := range metadata.Asset {
+		assets = append(assets, InspectedAsset{
+			Path:     asset.Path,
+			Hash:     asset.Hash,
+			MimeType: asset.EstimateMimeType(),
+			Size:     sizes[filepath.Base(asset.Hash)],
+		})
+	}
+	return &Inspection{
+		Source:       source,
+		Project:      metadata.Project,
+		Page:         metadata.Page,
+		Assets:       assets,
+		MetadataSize: int64(len(serialized)),
+		PayloadSize:  payload,
+	}, nil
+}
+
+// FileBlobSize reads the size of the blob from the source file.
+func FileBlobSize(blob MetadataBlob) (int64, error) {
+	info, err := os.Stat(blob.Filepath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", blob.Filepath, err)
+	}
+	return info.Size(), nil
+}
+
+// ArchiveBlobSize reads the size of the blob from the entries of the archive.
+func ArchiveBlobSize(archive *Archive) (BlobSizeFunc, error) {
+	sizes, err := archive.BlobSizes()
+	if err != nil {
+		return nil, err
+	}
+	return func(blob MetadataBlob) (int64, error) {
+		size, ok := sizes[blob.Hash]
+		if !ok {
+			return 0, fmt.Errorf("the blob of %s is not found in the archive", blob.Filepath)
+		}
+		return size, nil
+	}, nil
+}
+
+// Text renders the inspection as a plain report.
+func (i *Inspection) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Source: %s\n\n", i.Source)
+
+	b.WriteString("Project\n")
+	fields := [][2]string{
+		{"Project ID", i.Project.ProjectID},
+		{"Name", i.Project.Name},
+		{"Description", i.Project.Description},
+		{"Version", i.Project.Version},
+		{"Logo", i.Project.Logo},
+		{"Repository", i.Project.Repository},
+		{"Default Language", i.Project.DefaultLanguage},
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&b, "  %-18s%s\n", f[0]+":", f[1])
+	}
+
+	fmt.Fprintf(&b, "\nPages (%d)\n", i.Page.Count())
+	for _, line := range strings.Split(i.Page.String(), "\n") {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	fmt.Fprintf(&b, "\nAssets (%d)\n", len(i.Assets))
+	for _, a := range i.Assets {
+		fmt.Fprintf(&b, "  %s  %s  %s  %s\n", a.Path, a.MimeType, formatBytes(a.Size), a.Hash)
+	}
+
+	fmt.Fprintf(&b, "\nTotal payload: %s (metadata.json: %s)\n", formatBytes(i.PayloadSize), formatBytes(i.MetadataSize))
+	return b.String()
+}
+
+// Tree renders the page tree and the assets with box-drawing characters.
+func (i *Inspection) Tree() string {
+	root := tree.Root(fmt.Sprintf("%s (%s) %s", i.Project.Name, i.Project.ProjectID, formatBytes(i.PayloadSize)))
+	for _, child := range i.Page.Children {
+		root.Child(inspectionPageTree(&child))
+	}
+	if len(i.Assets) > 0 {
+		assets := tree.Root("assets")
+		for _, a := range i.Assets {
+			assets.Child(fmt.Sprintf("%s [%s, %s]", a.Path, a.MimeType, formatBytes(a.Size)))
+		}
+		root.Child(assets)
+	}
+	return root.String() + "\n"
+}
+
+func inspectionPageTree(p *Page) *tree.Tree {
+	node := tree.Root(p.Type)
+	for _, l := range p.Language {
+		label := fmt.Sprintf("%s: %s", l.Language, l.Title)
+		if l.Path != "" {
+			label += " -> " + l.Path
+		}
+		if l.Filepath != "" {
+			label += fmt.Sprintf(" (%s %s)", l.Filepath, shortHash(l.Hash))
+		}
+		node.Child(label)
+	}
+	for _, child := range p.Children {
+		node.Child(inspectionPageTree(&child))
+	}
+	return node
+}
+
+func shortHash(hash string) string {
+	const length = 12
+	if len(hash) > length {
+		return hash[:length]
+	}
+	return hash
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// UploadManifest is sent to the server before an incremental upload.
+// It lists every blob referenced by the metadata, so that the server can answer which blobs it does not have yet.
+// The hash of a blob is the sha256 of its contents, so it also serves as the digest.
+type UploadManifest struct {
+	Metadata *Metadata      `json:"metadata"`
+	Blobs    []ManifestBlob `json:"blobs"`
+}
+
+type ManifestBlob struct {
+	Hash string `json:"hash"` // the blob name under the `blobs` directory, which is the sha256 of the contents
+	Size int64  `json:"size"`
+}
+
+func NewUploadManifest(metadata *Metadata) (*UploadManifest, error) {
+	blobs := metadata.ListBlobs()
+	manifest := UploadManifest{
+		Metadata: metadata,
+		Blobs:    make([]ManifestBlob, 0, len(blobs)),
+	}
+	for _, blob := range blobs {
+		// The hash is computed while building the metadata. Only the size is read here.
+		info, err := os.Stat(blob.Filepath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat the file. File: %s: %w", blob.Filepath, err)
+		}
+		manifest.Blobs = append(manifest.Blobs, ManifestBlob{
+			Hash: blob.Hash,
+			Size: info.Size(),
+		})
+	}
+	return &manifest, nil
+}
+
+// SelectBlobs returns the blobs of the metadata whose hash is included in `hashes`.
+func (m *UploadManifest) SelectBlobs(hashes []string) []MetadataBlob {
+	wanted := make(map[string]struct{}, len(hashes))
+	for _, h := range hashes {
+		wanted[h] = struct{}{}
+	}
+	selected := make([]MetadataBlob, 0, len(hashes))
+	for _, blob := range m.Metadata.ListBlobs() {
+		if _, ok := wanted[blob.Hash]; ok {
+			selected = append(selected, blob)
+		}
+	}
+	return selected
+}
+
+// Size returns the total size of the blobs whose hash is included in `hashes`.
+func (m *UploadManifest) Size(hashes []string) int64 {
+	wanted := make(map[string]struct{}, len(hashes))
+	for _, h := range hashes {
+		wanted[h] = struct{}{}
+	}
+	var total int64
+	for _, blob := range m.Blobs {
+		if _, ok := wanted[blob.Hash]; ok {
+			total += blob.Size
+		}
+	}
+	return total
+}
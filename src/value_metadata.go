@@ -1,8 +1,6 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"mime"
@@ -13,6 +11,7 @@ import (
 	"github.com/caarlos0/log"
 	"github.com/toritoritori29/dodo-cli/src/config"
 	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
+	"github.com/toritoritori29/dodo-cli/src/utils"
 )
 
 var AvailableMimeTypes = []string{ //nolint: gochecknoglobals
@@ -100,6 +99,38 @@ func NewMetadataFromConfigV2(conf *config.ConfigV2) (*Metadata, error) {
 	return &metadata, nil
 }
 
+// MetadataBlob is a file stored under the `blobs` directory of the archive.
+type MetadataBlob struct {
+	Hash     string
+	Filepath string
+}
+
+// ListBlobs returns the markdown files of leaf pages and the assets, deduplicated by hash.
+func (m *Metadata) ListBlobs() []MetadataBlob {
+	seen := make(map[string]struct{})
+	blobs := make([]MetadataBlob, 0, len(m.Asset))
+	add := func(hash, path string) {
+		if _, ok := seen[hash]; ok {
+			return
+		}
+		seen[hash] = struct{}{}
+		blobs = append(blobs, MetadataBlob{Hash: hash, Filepath: path})
+	}
+
+	for _, page := range m.Page.ListPageHeader() {
+		if page.Type != PageTypeLeafNode {
+			continue
+		}
+		for _, lang := range page.Language {
+			add(lang.Hash, lang.Filepath)
+		}
+	}
+	for _, asset := range m.Asset {
+		add(filepath.Base(asset.Hash), asset.Path)
+	}
+	return blobs
+}
+
 func (m *Metadata) Serialize() ([]byte, error) {
 	s, err := json.Marshal(m)
 	if err != nil {
@@ -142,20 +173,30 @@ func NewMetadataProjectFromConfigV2(c *config.ConfigV2) MetadataProject {
 	}
 }
 
+// MetadataAsset is an image file referenced from the documents.
+// Path is where the file is located in the project and Hash is the sha256 of its contents,
+// which is used as the blob name in the archive.
 type MetadataAsset struct {
 	Path string `json:"path"`
 	Hash string `json:"hash"`
 }
 
 func NewMetadataAsset(path string) MetadataAsset {
-	sum := sha256.Sum256([]byte(path))
-	hash := hex.EncodeToString(sum[:])
 	return MetadataAsset{
-		path,
-		hash,
+		Path: path,
 	}
 }
 
+// ComputeHash reads the asset file and sets its content hash.
+func (a *MetadataAsset) ComputeHash() error {
+	hash, _, err := utils.HashFile(a.Path)
+	if err != nil {
+		return fmt.Errorf("failed to compute the hash of the asset: %w", err)
+	}
+	a.Hash = hash
+	return nil
+}
+
 func NewMetadataAssetFromConfig(c *config.ConfigV1, rootDir string) ([]MetadataAsset, *appErrors.MultiError) {
 	// Create Assets struct from config.
 	merr := appErrors.NewMultiError()
@@ -172,6 +213,10 @@ func NewMetadataAssetFromConfig(c *config.ConfigV1, rootDir string) ([]MetadataA
 				merr.Add(fmt.Errorf("asset file is invalid: %s: %w", f, err))
 				continue
 			}
+			if err = ma.ComputeHash(); err != nil {
+				merr.Add(err)
+				continue
+			}
 			metadataAssets = append(metadataAssets, ma)
 		}
 	}
@@ -181,6 +226,8 @@ func NewMetadataAssetFromConfig(c *config.ConfigV1, rootDir string) ([]MetadataA
 		logoAsset := NewMetadataAsset(c.Project.Logo)
 		if err := logoAsset.IsValidDataType(); err != nil {
 			merr.Add(err)
+		} else if err := logoAsset.ComputeHash(); err != nil {
+			merr.Add(err)
 		} else {
 			metadataAssets = append(metadataAssets, logoAsset)
 		}
@@ -219,6 +266,10 @@ func NewMetadataAssetFromConfigV2(c *config.ConfigV2, rootDir string) ([]Metadat
 				merr.Add(fmt.Errorf("asset file is invalid: %s: %w", f, err))
 				continue
 			}
+			if err = ma.ComputeHash(); err != nil {
+				merr.Add(err)
+				continue
+			}
 			metadataAssets = append(metadataAssets, ma)
 		}
 	}
@@ -228,6 +279,8 @@ func NewMetadataAssetFromConfigV2(c *config.ConfigV2, rootDir string) ([]Metadat
 		logoAsset := NewMetadataAsset(c.Project.Logo)
 		if err := logoAsset.IsValidDataType(); err != nil {
 			merr.Add(err)
+		} else if err := logoAsset.ComputeHash(); err != nil {
+			merr.Add(err)
 		} else {
 			metadataAssets = append(metadataAssets, logoAsset)
 		}
@@ -336,3 +336,36 @@ func TestMetadataAsset(t *testing.T) {
 	asset := NewMetadataAsset("test/image.png")
 	assert.Equal(t, "image/png", asset.EstimateMimeType())
 }
+
+func TestMetadataContentHash(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prepareFile(t, dir, "a.png", "same image")
+	prepareFile(t, dir, "b.png", "same image")
+	prepareFile(t, dir, "c.png", "another image")
+
+	hashes := make([]string, 0, 3)
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		asset := NewMetadataAsset(filepath.Join(dir, name))
+		require.NoError(t, asset.ComputeHash())
+		assert.Equal(t, filepath.Join(dir, name), asset.Path, "the path should be kept separately")
+		hashes = append(hashes, asset.Hash)
+	}
+	// The hash only depends on the contents, so renaming a file keeps its blob.
+	assert.Equal(t, hashes[0], hashes[1])
+	assert.NotEqual(t, hashes[0], hashes[2])
+
+	// Identical files are stored once.
+	metadata := &Metadata{
+		Page: Page{Type: PageTypeRootNode},
+		Asset: []MetadataAsset{
+			{Path: filepath.Join(dir, "a.png"), Hash: hashes[0]},
+			{Path: filepath.Join(dir, "b.png"), Hash: hashes[1]},
+			{Path: filepath.Join(dir, "c.png"), Hash: hashes[2]},
+		},
+	}
+	assert.Len(t, metadata.ListBlobs(), 2)
+
+	missing := NewMetadataAsset(filepath.Join(dir, "missing.png"))
+	require.Error(t, missing.ComputeHash())
+}
@@ -1,14 +1,15 @@
 package main
 
 import (
-	"crypto/sha256"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/caarlos0/log"
 	"github.com/toritoritori29/dodo-cli/src/config"
 	appErrors "github.com/toritoritori29/dodo-cli/src/errors"
+	"github.com/toritoritori29/dodo-cli/src/utils"
 )
 
 const (
@@ -39,22 +40,11 @@ type PageSummary struct {
 	UpdatedAt   config.SerializableTime `json:"updated_at"`
 }
 
-func NewPageSummary(filepath, path, title string) PageSummary {
-	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(filepath)))
-	return PageSummary{
-		Filepath: filepath,
-		Path:     path,
-		Hash:     hash,
-		Title:    title,
-	}
-}
-
 func NewPageHeaderFromPage(p *Page) PageSummary {
-	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(p.Filepath)))
 	return PageSummary{
 		Type:        p.Type,
 		Filepath:    p.Filepath,
-		Hash:        hash,
+		Hash:        p.Hash,
 		Path:        p.Path,
 		Title:       p.Title,
 		Description: p.Description,
@@ -84,7 +74,6 @@ func NewLeafNodeFromConfigPage(configProject *config.ConfigProjectV1, configPage
 				Description: configPage.Description,
 				Path:        configPage.Path,
 				Filepath:    configPage.Markdown,
-				Hash:        fmt.Sprintf("%x", sha256.Sum256([]byte(configPage.Markdown))),
 			},
 		},
 		UpdatedAt: configPage.UpdatedAt,
@@ -93,6 +82,16 @@ func NewLeafNodeFromConfigPage(configProject *config.ConfigProjectV1, configPage
 	return page
 }
 
+// LanguageInfo returns the language-wise information of the page for the given language.
+func (p *Page) LanguageInfo(lang string) (PageLanguageWiseInfo, bool) {
+	for _, l := range p.Language {
+		if l.Language == lang {
+			return l, true
+		}
+	}
+	return PageLanguageWiseInfo{}, false
+}
+
 // List PageSummary of the page includes.
 func (p *Page) ListPageHeader() []PageSummary {
 	list := make([]PageSummary, 0, len(p.Children))
@@ -191,17 +190,38 @@ func (p *Page) duplicationCount(pathMap map[string]int) {
 
 // Generate a string representation of the page.
 func (p *Page) String() string {
-	return p.buildString(0)
+	return strings.Join(p.TreeLines(true), "\n")
 }
 
-func (p *Page) buildString(depth int) string {
-	offset := strings.Repeat("-", depth*2)
-	lines := make([]string, 0, len(p.Children)+1)
-	lines = append(lines, fmt.Sprintf("%sTitle: %s, Path: %s", offset, p.Title, p.Path))
-	for _, c := range p.Children {
-		lines = append(lines, c.buildString(depth+1))
+// TreeLines returns the lines of the page tree: one for each page, followed by the lines of its languages.
+// The file paths are left out if withFilepath is false, e.g. to compare the trees built from different configs.
+func (p *Page) TreeLines(withFilepath bool) []string {
+	return p.appendTreeLines(nil, 0, withFilepath)
+}
+
+func (p *Page) appendTreeLines(lines []string, depth int, withFilepath bool) []string {
+	offset := strings.Repeat("  ", depth)
+	lines = append(lines, offset+p.Type)
+	for _, l := range p.Language {
+		line := fmt.Sprintf("%s  [%s] Title: %s", offset, l.Language, l.Title)
+		if l.Path != "" {
+			line += ", Link: " + l.Path
+		}
+		if l.Description != "" {
+			line += ", Description: " + l.Description
+		}
+		lines = append(lines, line)
+		switch {
+		case withFilepath && l.Filepath != "":
+			lines = append(lines, fmt.Sprintf("%s       Filepath: %s, Hash: %s", offset, l.Filepath, l.Hash))
+		case l.Hash != "":
+			lines = append(lines, fmt.Sprintf("%s       Hash: %s", offset, l.Hash))
+		}
 	}
-	return strings.Join(lines, "\n")
+	for i := range p.Children {
+		lines = p.Children[i].appendTreeLines(lines, depth+1, withFilepath)
+	}
+	return lines
 }
 
 // Count the number of pages.
@@ -268,12 +288,29 @@ func transformMarkdown(rootDir string, configProject *config.ConfigProjectV1, co
 		return nil, &merr
 	}
 
+	// The blob of the page is identified by the hash of its contents.
+	hash, _, err := utils.HashFile(resolvePagePath(rootDir, configPage.Markdown))
+	if err != nil {
+		merr.Add(fmt.Errorf("failed to compute the hash of the markdown: %w", err))
+		return nil, &merr
+	}
+
 	// First, populate the fields from the markdown front matter.
 	p := NewLeafNodeFromConfigPage(configProject, configPage)
+	p.Language[0].Hash = hash
 	log.Debugf("Node Found. Type: Markdown, Filepath: '%s', Title: '%s', Path: '%s'", p.Filepath, p.Title, p.Path)
 	return []Page{p}, nil
 }
 
+// resolvePagePath returns the location of a markdown file referenced from the config.
+// Paths expanded from `match` are already absolute, the others are relative to the root directory.
+func resolvePagePath(rootDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(rootDir, path)
+}
+
 func transformDirectory(rootDir string, configProject *config.ConfigProjectV1, configPage *config.ConfigPageV1) ([]Page, *appErrors.MultiError) {
 	merr := appErrors.NewMultiError()
 
@@ -364,16 +401,25 @@ func transformMarkdownV2(rootDir, defaultLang string, configPage *config.ConfigP
 
 	// Build language info for all languages
 	languageInfo := make([]PageLanguageWiseInfo, 0, len(configPage.LangPage))
-	for lang, lp := range configPage.LangPage {
+	for _, lang := range languageOrder(configPage.LangPage, defaultLang) {
+		lp := configPage.LangPage[lang]
+		hash, _, err := utils.HashFile(resolvePagePath(rootDir, lp.Filepath))
+		if err != nil {
+			merr.Add(fmt.Errorf("failed to compute the hash of the markdown: %w", err))
+			continue
+		}
 		languageInfo = append(languageInfo, PageLanguageWiseInfo{
 			Language:    lang,
 			Title:       lp.Title,
 			Description: lp.Description,
 			Path:        lp.Link,
 			Filepath:    lp.Filepath,
-			Hash:        fmt.Sprintf("%x", sha256.Sum256([]byte(lp.Filepath))),
+			Hash:        hash,
 		})
 	}
+	if merr.HasError() {
+		return nil, &merr
+	}
 
 	p := Page{
 		Type:     PageTypeLeafNode,
@@ -385,6 +431,16 @@ func transformMarkdownV2(rootDir, defaultLang string, configPage *config.ConfigP
 	return []Page{p}, nil
 }
 
+// languageOrder returns the languages of a page with the default language first and the rest in alphabetical order,
+// so that the metadata does not depend on the iteration order of the map.
+func languageOrder[V any](langs map[string]V, defaultLang string) []string {
+	order := utils.SortedKeys(langs)
+	if i := slices.Index(order, defaultLang); i > 0 {
+		order = slices.Concat([]string{defaultLang}, order[:i], order[i+1:])
+	}
+	return order
+}
+
 func transformSectionV2(rootDir string, configProject *config.ConfigProjectV2, configPage *config.ConfigPageV2) ([]Page, *appErrors.MultiError) {
 	merr := appErrors.NewMultiError()
 
@@ -400,7 +456,8 @@ func transformSectionV2(rootDir string, configProject *config.ConfigProjectV2, c
 
 	// Build language info for all languages
 	languageInfo := make([]PageLanguageWiseInfo, 0, len(configPage.LangSection))
-	for lang, ls := range configPage.LangSection {
+	for _, lang := range languageOrder(configPage.LangSection, configProject.GetDefaultLanguageOrFallback()) {
+		ls := configPage.LangSection[lang]
 		languageInfo = append(languageInfo, PageLanguageWiseInfo{
 			Language:    lang,
 			Title:       ls.Title,
@@ -436,7 +493,8 @@ func transformDirectoryV2(rootDir string, configProject *config.ConfigProjectV2,
 
 	// Build language info for all languages
 	languageInfo := make([]PageLanguageWiseInfo, 0, len(configPage.LangDirectory))
-	for lang, ld := range configPage.LangDirectory {
+	for _, lang := range languageOrder(configPage.LangDirectory, configProject.GetDefaultLanguageOrFallback()) {
+		ld := configPage.LangDirectory[lang]
 		languageInfo = append(languageInfo, PageLanguageWiseInfo{
 			Language:    lang,
 			Title:       ld.Title,
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// PulledConfig is the version-2 `.dodo.yaml` written by `dodo pull`.
+// The titles and the links of the pages are kept in the front matter of the markdown files.
+type PulledConfig struct {
+	Version int           `yaml:"version"`
+	Project PulledProject `yaml:"project"`
+	Pages   []PulledPage  `yaml:"pages"`
+	Assets  []string      `yaml:"assets,omitempty"`
+}
+
+type PulledProject struct {
+	ProjectID       string `yaml:"project_id"`
+	Name            string `yaml:"name"`
+	DefaultLanguage string `yaml:"default_language"`
+}
+
+// PulledPage is a page entry. Lang holds the entries in the order of the languages, the default one first.
+type PulledPage struct {
+	Type     string        `yaml:"type"`
+	Lang     yaml.MapSlice `yaml:"lang"`
+	Children []PulledPage  `yaml:"children,omitempty"`
+}
+
+type pulledLangPage struct {
+	Filepath string `yaml:"filepath"`
+}
+
+type pulledLangTitle struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+}
+
+func (c *PulledConfig) Serialize() ([]byte, error) {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the config: %w", err)
+	}
+	return b, nil
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`) //nolint: gochecknoglobals
+
+// pulledMarkdownPath returns the file name of the page, e.g. `intro.md` or `intro.ja.md`.
+// The default language has no suffix.
+func pulledMarkdownPath(link, lang, defaultLang string) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(link, "_"), "._")
+	if name == "" {
+		name = "index"
+	}
+	if lang == defaultLang {
+		return name + ".md"
+	}
+	return fmt.Sprintf("%s.%s.md", name, lang)
+}
+
+// pulledGroupID derives the language group of a page from its link, so that pulling twice gives the same files.
+func pulledGroupID(projectID, link string) string {
+	const length = 12
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(projectID+"/"+link)))[:length]
+}
+
+var markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*(?:<([^>]+)>|([^)\s]+))`) //nolint: gochecknoglobals
+
+// listAssetReferences returns the images the markdown refers to inside the project, relative to the project root.
+// External URLs and paths that leave the project are skipped.
+func listAssetReferences(markdown string) []string {
+	refs := []string{}
+	seen := map[string]struct{}{}
+	for _, match := range markdownImagePattern.FindAllStringSubmatch(markdown, -1) {
+		ref := match[1] + match[2] // the destination is either in angle brackets or not
+		if strings.Contains(ref, ":") || strings.HasPrefix(ref, "#") {
+			continue
+		}
+		if i := strings.IndexAny(ref, "?#"); i >= 0 {
+			ref = ref[:i]
+		}
+		ref = path.Clean(strings.TrimPrefix(ref, "/"))
+		if !filepath.IsLocal(ref) {
+			continue
+		}
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+	return refs
+}
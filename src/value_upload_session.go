@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	UploadSessionDirName  = "uploads"
+	UploadSessionFileName = "session.json"
+	UploadSessionArchive  = "archive.zip"
+)
+
+var ErrUploadSessionNotFound = errors.New("upload session not found")
+
+// UploadSession is the local state of a chunked upload.
+// It is saved together with a copy of the archive, so that an interrupted upload can be resumed
+// without rebuilding the archive.
+type UploadSession struct {
+	ID         string `json:"session_id"`
+	Endpoint   string `json:"endpoint"`
+	Digest     string `json:"digest"` // sha256 of the whole archive
+	Size       int64  `json:"size"`
+	ChunkSize  int64  `json:"chunk_size"`
+	ManifestID string `json:"manifest_id,omitempty"`
+}
+
+// Chunks returns the number of chunks the archive is split into.
+func (s *UploadSession) Chunks() int {
+	if s.Size == 0 {
+		return 1
+	}
+	return int((s.Size + s.ChunkSize - 1) / s.ChunkSize)
+}
+
+// ChunkRange returns the offset and the length of the chunk.
+func (s *UploadSession) ChunkRange(index int) (int64, int64) {
+	offset := int64(index) * s.ChunkSize
+	return offset, min(s.ChunkSize, s.Size-offset)
+}
+
+// UploadSessionStore keeps upload sessions under a directory, one subdirectory per session.
+type UploadSessionStore struct {
+	dir string
+}
+
+func NewUploadSessionStore(dir string) UploadSessionStore {
+	return UploadSessionStore{dir: dir}
+}
+
+// DefaultUploadSessionStore stores sessions in the user cache directory.
+func DefaultUploadSessionStore() (UploadSessionStore, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return UploadSessionStore{}, fmt.Errorf("failed to find the cache directory: %w", err)
+	}
+	return NewUploadSessionStore(filepath.Join(cacheDir, "dodo-cli", UploadSessionDirName)), nil
+}
+
+// Save writes the session and copies the archive into the store.
+func (s UploadSessionStore) Save(session *UploadSession, archive io.ReadSeeker) error {
+	dir := s.sessionDir(session.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create the session directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize the upload session: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, UploadSessionFileName), data, 0o600); err != nil {
+		return fmt.Errorf("failed to save the upload session: %w", err)
+	}
+
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek the archive file: %w", err)
+	}
+	file, err := os.Create(s.ArchivePath(session.ID))
+	if err != nil {
+		return fmt.Errorf("failed to create a copy of the archive: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+	if _, err := io.Copy(file, archive); err != nil {
+		return fmt.Errorf("failed to copy the archive: %w", err)
+	}
+	return nil
+}
+
+func (s UploadSessionStore) Load(id string) (*UploadSession, error) {
+	data, err := os.ReadFile(filepath.Join(s.sessionDir(id), UploadSessionFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrUploadSessionNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the upload session: %w", err)
+	}
+	session := UploadSession{}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse the upload session: %w", err)
+	}
+	return &session, nil
+}
+
+func (s UploadSessionStore) Remove(id string) error {
+	if err := os.RemoveAll(s.sessionDir(id)); err != nil {
+		return fmt.Errorf("failed to remove the upload session: %w", err)
+	}
+	return nil
+}
+
+func (s UploadSessionStore) ArchivePath(id string) string {
+	return filepath.Join(s.sessionDir(id), UploadSessionArchive)
+}
+
+func (s UploadSessionStore) sessionDir(id string) string {
+	// The ID comes from the server or the command line, so never let it escape the store.
+	return filepath.Join(s.dir, filepath.Base(filepath.Clean("/"+id)))
+}